@@ -39,15 +39,11 @@ func TestApiScrapeSuccess(t *testing.T) {
 		t.Fatalf("Request failed: %v", err)
 	}
 
-	// For scrape tests, we accept either success or rate limit
-	// since the test might hit rate limits on repeated runs
-	if resp.StatusCode != 200 && resp.StatusCode != 429 {
+	// client retries 429s itself (honoring Retry-After), so a non-200 here
+	// is a genuine failure rather than transient rate limiting.
+	if resp.StatusCode != 200 {
 		body := ReadBody(t, resp)
-		t.Fatalf("Expected 200 or 429, got %d: %s", resp.StatusCode, body)
-	}
-
-	if resp.StatusCode == 429 {
-		t.Skip("Rate limited, skipping success check")
+		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, body)
 	}
 
 	var result ScrapeResult
@@ -89,11 +85,6 @@ func TestApiScrapeOutputTypes(t *testing.T) {
 				t.Fatalf("Request failed: %v", err)
 			}
 
-			// Accept rate limiting
-			if resp.StatusCode == 429 {
-				t.Skip("Rate limited")
-			}
-
 			if resp.StatusCode != 200 {
 				body := ReadBody(t, resp)
 				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, body)
@@ -148,11 +139,6 @@ func TestApiScrapeStats(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	// Accept rate limiting
-	if resp.StatusCode == 429 {
-		t.Skip("Rate limited")
-	}
-
 	// Wait for async stats persistence
 	time.Sleep(200 * time.Millisecond)
 
@@ -195,11 +181,6 @@ func TestApiScrapeLogs(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	// Accept rate limiting
-	if resp.StatusCode == 429 {
-		t.Skip("Rate limited")
-	}
-
 	// Wait for async log write
 	time.Sleep(200 * time.Millisecond)
 
@@ -252,11 +233,6 @@ func TestApiScrapeXpathOverride(t *testing.T) {
 		t.Fatalf("Request failed: %v", err)
 	}
 
-	// Accept rate limiting
-	if resp.StatusCode == 429 {
-		t.Skip("Rate limited")
-	}
-
 	if resp.StatusCode != 200 {
 		body := ReadBody(t, resp)
 		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, body)
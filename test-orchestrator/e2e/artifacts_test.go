@@ -0,0 +1,131 @@
+package e2e
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Range/multipart support on /api/artifacts is server-side work with no
+// source in this repo to add it to, so every test in this file is
+// blocked/out-of-scope here: they skip on a 404 and will only ever run
+// for real against a server built elsewhere that already implements it.
+
+// TestArtifactRangeFullDownload verifies that a plain (no Range header)
+// GET of a cached artifact returns the full body with 200 and an ETag.
+func TestArtifactRangeFullDownload(t *testing.T) {
+	baseURL := GetBaseURL(t)
+	client := NewTestClient(GetAPIToken(t))
+
+	resp, err := client.Get(baseURL + "/api/artifacts/smoke-test")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		t.Skip("/api/artifacts not wired up in this environment")
+	}
+	AssertStatus(t, resp, 200)
+
+	if resp.Header.Get("ETag") == "" {
+		t.Error("Expected ETag header on artifact response")
+	}
+	if resp.Header.Get("Accept-Ranges") != "bytes" {
+		t.Errorf("Expected Accept-Ranges: bytes, got %q", resp.Header.Get("Accept-Ranges"))
+	}
+}
+
+// TestArtifactRangeSingleByteRange verifies a simple single-range request
+// (bytes=5-1000) returns 206 with a Content-Range header.
+func TestArtifactRangeSingleByteRange(t *testing.T) {
+	baseURL := GetBaseURL(t)
+	client := NewTestClient(GetAPIToken(t))
+
+	req, err := http.NewRequest("GET", baseURL+"/api/artifacts/smoke-test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	req.Header.Set("Range", "bytes=5-1000")
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		t.Skip("/api/artifacts not wired up in this environment")
+	}
+	AssertStatus(t, resp, 206)
+
+	contentRange := resp.Header.Get("Content-Range")
+	if contentRange == "" {
+		t.Error("Expected Content-Range header on 206 response")
+	}
+}
+
+// TestArtifactRangeUnsatisfiable verifies that a Range starting past the
+// end of the artifact returns 416 with Content-Range: bytes */size.
+func TestArtifactRangeUnsatisfiable(t *testing.T) {
+	baseURL := GetBaseURL(t)
+	client := NewTestClient(GetAPIToken(t))
+
+	req, err := http.NewRequest("GET", baseURL+"/api/artifacts/smoke-test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	req.Header.Set("Range", "bytes=999999999-1000000000")
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		t.Skip("/api/artifacts not wired up in this environment")
+	}
+	AssertStatus(t, resp, 416)
+
+	contentRange := resp.Header.Get("Content-Range")
+	if contentRange == "" || contentRange[:len("bytes */")] != "bytes */" {
+		t.Errorf(`Expected Content-Range: bytes */<size>, got %q`, contentRange)
+	}
+}
+
+// TestArtifactRangeMultiRange verifies that a comma-separated multi-range
+// request (bytes=0-0,-2) returns a multipart/byteranges response.
+func TestArtifactRangeMultiRange(t *testing.T) {
+	baseURL := GetBaseURL(t)
+	client := NewTestClient(GetAPIToken(t))
+
+	req, err := http.NewRequest("GET", baseURL+"/api/artifacts/smoke-test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	req.Header.Set("Range", "bytes=0-0,-2")
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		t.Skip("/api/artifacts not wired up in this environment")
+	}
+	AssertStatus(t, resp, 206)
+
+	contentType := resp.Header.Get("Content-Type")
+	if !containsMultipartByteranges(contentType) {
+		t.Errorf("Expected Content-Type: multipart/byteranges for multi-range request, got %q", contentType)
+	}
+}
+
+func containsMultipartByteranges(contentType string) bool {
+	const want = "multipart/byteranges"
+	return len(contentType) >= len(want) && contentType[:len(want)] == want
+}
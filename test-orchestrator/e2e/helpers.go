@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -51,7 +52,9 @@ func GetAPIToken(t *testing.T) string {
 
 // --- HTTP Client ---
 
-// TestClient is an HTTP client with automatic auth header injection.
+// TestClient is an HTTP client with automatic auth header injection. It owns
+// a single cookiejar.Jar for its lifetime, so a client reused across t.Run
+// sub-tests (or handed to LoginWithToken) carries its session cookie along.
 type TestClient struct {
 	http  *http.Client
 	token string
@@ -69,6 +72,52 @@ func NewTestClient(apiToken string) *TestClient {
 	}
 }
 
+// maxRateLimitRetries bounds how many times doWithRetry will retry a 429
+// response before giving up and returning it to the caller.
+const maxRateLimitRetries = 3
+
+// defaultRetryAfter is used when a 429 response omits (or sends an
+// unparseable) Retry-After header.
+const defaultRetryAfter = 500 * time.Millisecond
+
+// doWithRetry sends req and, on a 429 response, sleeps for the duration
+// indicated by Retry-After and resends it, up to maxRateLimitRetries times,
+// instead of surfacing the rate limit to the caller. This keeps scrape tests
+// reliable under parallel worker load without each test hand-rolling a skip.
+func (c *TestClient) doWithRetry(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.http.Do(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt == maxRateLimitRetries {
+			return resp, err
+		}
+
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"))
+		resp.Body.Close()
+		time.Sleep(wait)
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// parseRetryAfter interprets a Retry-After header value (seconds, per RFC
+// 9110) falling back to defaultRetryAfter when absent or malformed.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return defaultRetryAfter
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs < 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(secs) * time.Second
+}
+
 // Get performs a GET request with auth header.
 func (c *TestClient) Get(targetURL string) (*http.Response, error) {
 	req, err := http.NewRequest("GET", targetURL, nil)
@@ -76,7 +125,7 @@ func (c *TestClient) Get(targetURL string) (*http.Response, error) {
 		return nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
-	return c.http.Do(req)
+	return c.doWithRetry(req)
 }
 
 // PostJSON performs a POST request with JSON body and auth header.
@@ -92,7 +141,7 @@ func (c *TestClient) PostJSON(targetURL string, body interface{}) (*http.Respons
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
-	return c.http.Do(req)
+	return c.doWithRetry(req)
 }
 
 // PostForm performs a POST request with form data and auth header.
@@ -103,7 +152,7 @@ func (c *TestClient) PostForm(targetURL string, data url.Values) (*http.Response
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	return c.http.Do(req)
+	return c.doWithRetry(req)
 }
 
 // Delete performs a DELETE request with auth header.
@@ -113,7 +162,37 @@ func (c *TestClient) Delete(targetURL string) (*http.Response, error) {
 		return nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
-	return c.http.Do(req)
+	return c.doWithRetry(req)
+}
+
+// LoginWithToken performs the POST /login -> session cookie flow against
+// baseURL, storing the resulting session cookie in this client's jar so
+// subsequent requests (e.g. to /dashboard/...) are authenticated by cookie
+// instead of the Authorization header. Fails the test on any error.
+func (c *TestClient) LoginWithToken(t *testing.T, baseURL string) {
+	t.Helper()
+
+	form := url.Values{}
+	form.Set("token", c.token)
+
+	req, err := http.NewRequest("POST", baseURL+"/login", bytes.NewReader([]byte(form.Encode())))
+	if err != nil {
+		t.Fatalf("Failed to create login request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		t.Fatalf("Login request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Login should redirect (302) to dashboard; the client follows redirects
+	// automatically, so a plain 200 is also acceptable.
+	if resp.StatusCode != 200 && resp.StatusCode != 302 {
+		body := ReadBody(t, resp)
+		t.Fatalf("Expected login to succeed, got status %d: %s", resp.StatusCode, body)
+	}
 }
 
 // --- Domain Models ---
@@ -138,6 +217,7 @@ type SiteConfig struct {
 	SiteSpecificHeaders           map[string]string `json:"siteSpecificHeaders,omitempty"`
 	SiteCleanupClasses            []string          `json:"siteCleanupClasses,omitempty"`
 	UserAgent                     string            `json:"userAgent,omitempty"`
+	CacheTtlSeconds               int               `json:"cacheTtlSeconds,omitempty"`
 }
 
 // Stats represents the stats.json structure
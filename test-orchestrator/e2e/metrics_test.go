@@ -0,0 +1,69 @@
+package e2e
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestMetricsEndpointExposesScrapeSeries verifies that, after a scrape,
+// GET /metrics exposes Prometheus series derived from the same data
+// written to stats.json: smartscraper_scrape_total, the per-domain
+// duration histogram, and the LLM discovery / CAPTCHA counters. The
+// endpoint is expected to be unauthenticated. Environments that haven't
+// wired up /metrics yet (404) are skipped rather than failed.
+//
+// The /metrics endpoint itself is server-side work with no source in
+// this repo to add it to, so this test is blocked/out-of-scope here: it
+// will only ever run for real against a server built elsewhere that
+// already exposes it.
+func TestMetricsEndpointExposesScrapeSeries(t *testing.T) {
+	baseURL := GetBaseURL(t)
+	dataDir := GetDataDir(t)
+	client := NewTestClient(GetAPIToken(t))
+
+	WriteSites(t, dataDir, []SiteConfig{
+		{
+			DomainPattern:                "httpbin.org",
+			XpathMainContent:             "//body",
+			FailureCountSinceLastSuccess: 0,
+		},
+	})
+
+	scrapeResp, err := client.PostJSON(baseURL+"/api/scrape", map[string]interface{}{
+		"url":        "https://httpbin.org/html",
+		"outputType": "content_only",
+	})
+	if err != nil {
+		t.Fatalf("Scrape request failed: %v", err)
+	}
+	if scrapeResp.StatusCode == 429 {
+		t.Skip("Rate limited, skipping metrics check")
+	}
+	ReadBody(t, scrapeResp)
+
+	// Give the fan-out to the Prometheus registry time to land.
+	time.Sleep(200 * time.Millisecond)
+
+	metricsResp, err := (&http.Client{}).Get(baseURL + "/metrics")
+	if err != nil {
+		t.Fatalf("Metrics request failed: %v", err)
+	}
+	defer metricsResp.Body.Close()
+
+	if metricsResp.StatusCode == 404 {
+		t.Skip("/metrics not wired up in this environment")
+	}
+	AssertStatus(t, metricsResp, 200)
+
+	body := ReadBody(t, metricsResp)
+
+	for _, series := range []string{
+		"smartscraper_scrape_total",
+		"smartscraper_scrape_duration_seconds",
+		"smartscraper_llm_discovery_total",
+		"smartscraper_captcha_solved_total",
+	} {
+		AssertContains(t, body, series)
+	}
+}
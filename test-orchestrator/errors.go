@@ -21,8 +21,12 @@ var (
 	// Test execution
 	ErrTestsFailed  = errors.New("go test execution failed")
 	ErrNoTestsFound = errors.New("no test files found")
+	ErrShardEmpty   = errors.New("shard has no tests to run")
 
 	// Worker management
 	ErrWorkerNotAvailable = errors.New("no worker available")
 	ErrWorkerStartFailed  = errors.New("worker failed to start")
+
+	// Port allocation
+	ErrNoPortAvailable = errors.New("no free port available")
 )
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// TestEvent mirrors a single JSON event emitted by `go test -json`, per the
+// schema documented in `go help testflag`: one object per line, with
+// Action one of run/pause/cont/bench/output/pass/fail/skip.
+type TestEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package,omitempty"`
+	Test    string    `json:"Test,omitempty"`
+	Elapsed float64   `json:"Elapsed,omitempty"`
+	Output  string    `json:"Output,omitempty"`
+}
+
+// decodeTestEvents streams the newline-delimited JSON event stream out of
+// output, decoding one object per line. Lines that aren't valid JSON (e.g.
+// build failures printed before -json output starts) are skipped rather
+// than treated as a fatal decode error.
+func decodeTestEvents(output string) []TestEvent {
+	var events []TestEvent
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		dec := json.NewDecoder(strings.NewReader(scanner.Text()))
+		var ev TestEvent
+		if err := dec.Decode(&ev); err != nil {
+			continue
+		}
+		events = append(events, ev)
+	}
+
+	return events
+}
+
+// TestCaseResult is a normalized, aggregated view of one test or subtest,
+// built from the raw -json event stream.
+type TestCaseResult struct {
+	Name     string
+	Action   string // "pass", "fail", or "skip"
+	Elapsed  time.Duration
+	Output   string
+	WorkerID int
+	DataDir  string // the worker's DATA_DIR this test ran against, if any
+	CacheHit bool   // true for tests skipped via the file-hash cache, never actually run
+	Subtests []*TestCaseResult
+}
+
+// Passed reports whether this result and all of its subtests passed.
+func (r *TestCaseResult) Passed() bool {
+	if r.Action == "fail" {
+		return false
+	}
+	for _, sub := range r.Subtests {
+		if !sub.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregateTestEvents groups a flat -json event stream into a tree of
+// TestCaseResult keyed by Test name. Subtests are reported by go test under
+// "Parent/Child" names and are attached as children of their parent so each
+// becomes its own entry in downstream reports.
+func aggregateTestEvents(events []TestEvent, topLevelFunc string, workerID int) *TestCaseResult {
+	nodes := make(map[string]*TestCaseResult)
+	var order []string
+
+	nodeFor := func(name string) *TestCaseResult {
+		if n, ok := nodes[name]; ok {
+			return n
+		}
+		n := &TestCaseResult{Name: name, WorkerID: workerID}
+		nodes[name] = n
+		order = append(order, name)
+		return n
+	}
+
+	for _, ev := range events {
+		if ev.Test == "" {
+			continue
+		}
+		node := nodeFor(ev.Test)
+		switch ev.Action {
+		case "output":
+			node.Output += ev.Output
+		case "pass", "fail", "skip":
+			node.Action = ev.Action
+			node.Elapsed = time.Duration(ev.Elapsed * float64(time.Second))
+		}
+	}
+
+	for _, name := range order {
+		idx := strings.LastIndex(name, "/")
+		if idx <= 0 {
+			continue
+		}
+		if parent, ok := nodes[name[:idx]]; ok {
+			parent.Subtests = append(parent.Subtests, nodes[name])
+		}
+	}
+
+	if top, ok := nodes[topLevelFunc]; ok {
+		return top
+	}
+	return &TestCaseResult{Name: topLevelFunc, WorkerID: workerID}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FlakyFile is the default filename for the flaky-test report sidecar.
+const FlakyFile = "flaky.json"
+
+// FlakyEntry records a test that failed at least once before eventually
+// passing within the configured retry budget.
+type FlakyEntry struct {
+	FuncName  string `json:"funcName"`
+	Attempts  int    `json:"attempts"`
+	ElapsedMs int64  `json:"elapsedMs"`
+	LastRun   int64  `json:"lastRun"`
+}
+
+// FlakyReport accumulates flaky classifications for a single orchestrator run.
+type FlakyReport struct {
+	Entries []FlakyEntry `json:"entries"`
+	mu      sync.Mutex
+}
+
+// NewFlakyReport creates an empty flaky report.
+func NewFlakyReport() *FlakyReport {
+	return &FlakyReport{}
+}
+
+// Record adds a flaky classification for a test that passed after N>1 attempts.
+func (r *FlakyReport) Record(funcName string, attempts int, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Entries = append(r.Entries, FlakyEntry{
+		FuncName:  funcName,
+		Attempts:  attempts,
+		ElapsedMs: elapsed.Milliseconds(),
+		LastRun:   time.Now().Unix(),
+	})
+}
+
+// Save writes the report to path as JSON. If no flaky tests were recorded,
+// it leaves any existing sidecar untouched rather than writing an empty file.
+func (r *FlakyReport) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.Entries) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal flaky report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write flaky report: %w", err)
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -31,17 +33,51 @@ func (h *HealthChecker) Wait(ctx context.Context) error {
 	return h.WaitWithProgress(ctx, nil)
 }
 
+// CheckOnce performs a single health check request and classifies the
+// result: ok (200), throttled (429/503, with Retry-After parsed if
+// present), or a plain connection/status error. WaitWithProgress and
+// ReadinessPlan's http-health gate (readiness.go) both build on this so
+// they honor Retry-After the same way instead of duplicating the request
+// and classification logic.
+func (h *HealthChecker) CheckOnce(ctx context.Context) (ok bool, throttled bool, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return false, false, 0, err
+	}
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, doErr := client.Do(req)
+	if doErr != nil {
+		return false, false, 0, doErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return true, false, 0, nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return false, true, parseRetryAfter(resp.Header.Get("Retry-After")), nil
+	}
+
+	return false, false, 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
 // WaitWithProgress polls the health endpoint with a progress callback.
-// The onAttempt callback is called before each attempt with the attempt number (1-based)
-// and elapsed time since the start of waiting.
-func (h *HealthChecker) WaitWithProgress(ctx context.Context, onAttempt func(attempt int, elapsed time.Duration)) error {
+// The onAttempt callback is called after each attempt with the attempt
+// number (1-based), elapsed time since the start of waiting (taken just
+// before that attempt's request), and whether that attempt's response
+// looked like server-side throttling (429/503) rather than "not listening
+// yet", so verbose mode can log the two cases distinctly.
+//
+// On a 429/503 carrying a Retry-After header, the wait honors that hint
+// directly (capped at MaxBackoff*4) and resets the exponential backoff
+// counter, on the theory that the server knows its own recovery time
+// better than our local backoff schedule does. Connection errors and
+// 5xx/429 without a hint fall back to the existing exponential backoff.
+func (h *HealthChecker) WaitWithProgress(ctx context.Context, onAttempt func(attempt int, elapsed time.Duration, throttled bool)) error {
 	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
 	defer cancel()
 
-	client := &http.Client{
-		Timeout: 2 * time.Second,
-	}
-
 	startTime := time.Now()
 	backoff := h.InitialBackoff
 	attempt := 0
@@ -50,10 +86,6 @@ func (h *HealthChecker) WaitWithProgress(ctx context.Context, onAttempt func(att
 		attempt++
 		elapsed := time.Since(startTime)
 
-		if onAttempt != nil {
-			onAttempt(attempt, elapsed)
-		}
-
 		// Check if context is already cancelled before making request
 		select {
 		case <-ctx.Done():
@@ -61,31 +93,68 @@ func (h *HealthChecker) WaitWithProgress(ctx context.Context, onAttempt func(att
 		default:
 		}
 
-		// Make the health check request
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
-		if err != nil {
-			return err
+		ok, throttled, retryAfter, _ := h.CheckOnce(ctx)
+
+		if ok {
+			if onAttempt != nil {
+				onAttempt(attempt, elapsed, false)
+			}
+			return nil
 		}
 
-		resp, err := client.Do(req)
-		if err == nil {
-			resp.Body.Close()
-			if resp.StatusCode == http.StatusOK {
-				return nil
+		wait := backoff
+		usedHint := false
+		if throttled && retryAfter > 0 {
+			wait = retryAfter
+			if capped := h.MaxBackoff * 4; wait > capped {
+				wait = capped
 			}
+			usedHint = true
+		}
+
+		if onAttempt != nil {
+			onAttempt(attempt, elapsed, throttled)
 		}
 
-		// Wait before next attempt with exponential backoff
+		// Wait before next attempt - either the server's own hint, or the
+		// local exponential backoff.
 		select {
 		case <-ctx.Done():
 			return ErrHealthCheckTimeout
-		case <-time.After(backoff):
+		case <-time.After(wait):
 		}
 
-		// Increase backoff for next iteration
-		backoff = time.Duration(float64(backoff) * h.BackoffFactor)
-		if backoff > h.MaxBackoff {
-			backoff = h.MaxBackoff
+		if usedHint {
+			backoff = h.InitialBackoff
+		} else {
+			backoff = time.Duration(float64(backoff) * h.BackoffFactor)
+			if backoff > h.MaxBackoff {
+				backoff = h.MaxBackoff
+			}
 		}
 	}
 }
+
+// parseRetryAfter parses a Retry-After header in either its seconds form
+// ("120") or HTTP-date form, returning 0 if the header is absent, invalid,
+// or already in the past.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
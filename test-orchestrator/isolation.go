@@ -3,12 +3,17 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
-// IsolatedEnv represents an ephemeral isolated environment for a test worker.
-// Each worker gets its own temp directory with isolated storage files.
+// IsolatedEnv represents an ephemeral isolated environment for a test
+// worker. Each worker gets its own ephemeral storage: sites.jsonc,
+// stats.json, and a logs directory. DataDir's meaning depends on Kind: for
+// "tmpdir"/"tmpfs" it's a host filesystem path; for "docker" it's the name
+// of a docker volume rather than a path (see dockerVolumeIsolation).
 type IsolatedEnv struct {
 	ID        int
 	DataDir   string
@@ -16,72 +21,226 @@ type IsolatedEnv struct {
 	StatsFile string
 	LogsDir   string
 	Port      int
+	Kind      string // "tmpdir" (default), "tmpfs", or "docker" - which Isolation produced this
 }
 
-// CreateIsolatedEnv creates a new isolated environment for the given worker.
-// It creates a temp directory with the pattern /tmp/smartscraper-test-{id}-{timestamp}/
-// and initializes the required files and directories.
-func CreateIsolatedEnv(workerID int) (*IsolatedEnv, error) {
-	timestamp := time.Now().Unix()
-	baseDir := fmt.Sprintf("/tmp/smartscraper-test-%d-%d", workerID, timestamp)
+// Isolation provisions and tears down a worker's ephemeral, file-based
+// environment. It is orthogonal to Isolator (isolator.go), which controls
+// how the Hono *process* itself is launched - any Isolation backend can be
+// paired with any Isolator backend, though in practice "docker" Isolation
+// only makes sense paired with a "docker"/"podman" Isolator (see its doc
+// comment for the current limitation).
+type Isolation interface {
+	// Name identifies this backend, e.g. "tmpdir", "tmpfs", "docker".
+	Name() string
 
-	// Create the base directory
+	// Setup provisions a fresh environment for workerID, bound to port (as
+	// leased by the caller's PortAllocator).
+	Setup(workerID int, port int) (*IsolatedEnv, error)
+
+	// Env returns the environment variables a worker process needs in order
+	// to use env.
+	Env(env *IsolatedEnv) []string
+
+	// Cleanup removes everything Setup created for env.
+	Cleanup(env *IsolatedEnv) error
+
+	// URL returns the base HTTP URL for the worker's server.
+	URL(env *IsolatedEnv) string
+}
+
+// NewIsolation selects an Isolation backend by name, as chosen via
+// --isolation-kind. An empty name defaults to "tmpdir".
+func NewIsolation(kind string) (Isolation, error) {
+	switch kind {
+	case "", "tmpdir":
+		return tmpDirIsolation{}, nil
+	case "tmpfs":
+		return tmpfsIsolation{}, nil
+	case "docker":
+		return dockerVolumeIsolation{}, nil
+	default:
+		return nil, fmt.Errorf("unknown --isolation-kind %q (want tmpdir, tmpfs, or docker)", kind)
+	}
+}
+
+// newBaseIsolatedEnv creates baseDir plus its logs/sites.jsonc/stats.json
+// scaffolding, shared by every host-filesystem-backed Isolation.
+func newBaseIsolatedEnv(workerID int, baseDir, kind string, port int) (*IsolatedEnv, error) {
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrIsolationCreateFailed, err)
 	}
 
-	// Create logs subdirectory
 	logsDir := filepath.Join(baseDir, "logs")
 	if err := os.MkdirAll(logsDir, 0755); err != nil {
-		// Cleanup on failure
 		os.RemoveAll(baseDir)
 		return nil, fmt.Errorf("%w: failed to create logs dir: %v", ErrIsolationCreateFailed, err)
 	}
 
-	// Initialize sites.jsonc with empty array
 	sitesFile := filepath.Join(baseDir, "sites.jsonc")
 	if err := os.WriteFile(sitesFile, []byte("[]"), 0644); err != nil {
 		os.RemoveAll(baseDir)
 		return nil, fmt.Errorf("%w: failed to create sites.jsonc: %v", ErrIsolationCreateFailed, err)
 	}
 
-	// Initialize stats.json with empty object
 	statsFile := filepath.Join(baseDir, "stats.json")
 	if err := os.WriteFile(statsFile, []byte("{}"), 0644); err != nil {
 		os.RemoveAll(baseDir)
 		return nil, fmt.Errorf("%w: failed to create stats.json: %v", ErrIsolationCreateFailed, err)
 	}
 
-	env := &IsolatedEnv{
+	return &IsolatedEnv{
 		ID:        workerID,
 		DataDir:   baseDir,
 		SitesFile: sitesFile,
 		StatsFile: statsFile,
 		LogsDir:   logsDir,
-		Port:      9000 + workerID,
+		Port:      port,
+		Kind:      kind,
+	}, nil
+}
+
+func envFor(env *IsolatedEnv) []string {
+	return []string{
+		fmt.Sprintf("DATA_DIR=%s", env.DataDir),
+		fmt.Sprintf("PORT=%d", env.Port),
+		fmt.Sprintf("API_TOKEN=test-token-%d", env.ID),
+	}
+}
+
+func urlFor(env *IsolatedEnv) string {
+	return fmt.Sprintf("http://127.0.0.1:%d", env.Port)
+}
+
+// tmpDirIsolation is the original backend: a plain /tmp directory per
+// worker, on whatever filesystem the orchestrator itself runs on.
+type tmpDirIsolation struct{}
+
+func (tmpDirIsolation) Name() string { return "tmpdir" }
+
+func (tmpDirIsolation) Setup(workerID int, port int) (*IsolatedEnv, error) {
+	baseDir := fmt.Sprintf("/tmp/smartscraper-test-%d-%d", workerID, time.Now().Unix())
+	return newBaseIsolatedEnv(workerID, baseDir, "tmpdir", port)
+}
+
+func (tmpDirIsolation) Env(env *IsolatedEnv) []string { return envFor(env) }
+
+func (tmpDirIsolation) Cleanup(env *IsolatedEnv) error {
+	if err := os.RemoveAll(env.DataDir); err != nil {
+		return fmt.Errorf("%w: %v", ErrIsolationCleanupFailed, err)
 	}
+	return nil
+}
+
+func (tmpDirIsolation) URL(env *IsolatedEnv) string { return urlFor(env) }
+
+// tmpfsIsolation mounts a tmpfs over the worker's base directory before
+// populating it, so the isolated environment's I/O never touches disk -
+// useful for fast CI runners with tight disk I/O budgets. Requires
+// permission to mount (typically root, or a user namespace that allows it);
+// Setup surfaces the mount failure rather than silently falling back to a
+// plain directory.
+type tmpfsIsolation struct{}
 
+func (tmpfsIsolation) Name() string { return "tmpfs" }
+
+func (tmpfsIsolation) Setup(workerID int, port int) (*IsolatedEnv, error) {
+	baseDir := fmt.Sprintf("/tmp/smartscraper-test-%d-%d", workerID, time.Now().Unix())
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIsolationCreateFailed, err)
+	}
+
+	mount := exec.Command("mount", "-t", "tmpfs", "-o", "size=64m", "tmpfs", baseDir)
+	if output, err := mount.CombinedOutput(); err != nil {
+		os.RemoveAll(baseDir)
+		return nil, fmt.Errorf("%w: tmpfs mount failed: %v (output: %s)", ErrIsolationCreateFailed, err, strings.TrimSpace(string(output)))
+	}
+
+	env, err := newBaseIsolatedEnv(workerID, baseDir, "tmpfs", port)
+	if err != nil {
+		_ = exec.Command("umount", baseDir).Run()
+		os.RemoveAll(baseDir)
+		return nil, err
+	}
 	return env, nil
 }
 
-// Cleanup removes the entire DataDir recursively.
-func (e *IsolatedEnv) Cleanup() error {
-	if err := os.RemoveAll(e.DataDir); err != nil {
+func (tmpfsIsolation) Env(env *IsolatedEnv) []string { return envFor(env) }
+
+func (tmpfsIsolation) Cleanup(env *IsolatedEnv) error {
+	if output, err := exec.Command("umount", env.DataDir).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: umount failed: %v (output: %s)", ErrIsolationCleanupFailed, err, strings.TrimSpace(string(output)))
+	}
+	if err := os.RemoveAll(env.DataDir); err != nil {
 		return fmt.Errorf("%w: %v", ErrIsolationCleanupFailed, err)
 	}
 	return nil
 }
 
-// Env returns environment variables for the worker process.
-func (e *IsolatedEnv) Env() []string {
+func (tmpfsIsolation) URL(env *IsolatedEnv) string { return urlFor(env) }
+
+// dockerVolumeIsolation backs DATA_DIR with a named docker volume instead of
+// a host path, for pairing with a "docker"/"podman" Isolator so the Hono
+// server never touches the host filesystem at all.
+//
+// Known limitation: e2e test helpers (ReadSites, WriteStats, ReadLogs, ...)
+// read and write DataDir directly from the host via os.ReadFile/os.WriteFile,
+// which only works for a host path. A docker-volume-backed DATA_DIR is not
+// host-readable this way, so pairing this backend with the e2e suite's
+// current file-inspection helpers requires either mounting the volume on the
+// host too (e.g. via a local driver pointed at a host path) or extending
+// those helpers to go through `docker cp`/`docker exec` - left as future
+// work rather than silently worked around here.
+type dockerVolumeIsolation struct{}
+
+func (dockerVolumeIsolation) Name() string { return "docker" }
+
+func (dockerVolumeIsolation) Setup(workerID int, port int) (*IsolatedEnv, error) {
+	volumeName := fmt.Sprintf("smartscraper-test-worker-%d-%d", workerID, time.Now().Unix())
+	if output, err := exec.Command("docker", "volume", "create", volumeName).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%w: docker volume create failed: %v (output: %s)", ErrIsolationCreateFailed, err, strings.TrimSpace(string(output)))
+	}
+
+	const containerDataDir = "/data"
+	initScript := fmt.Sprintf(
+		"mkdir -p %s/logs && [ -f %s/sites.jsonc ] || echo '[]' > %s/sites.jsonc && [ -f %s/stats.json ] || echo '{}' > %s/stats.json",
+		containerDataDir, containerDataDir, containerDataDir, containerDataDir, containerDataDir,
+	)
+	initCmd := exec.Command("docker", "run", "--rm", "-v", volumeName+":"+containerDataDir, defaultContainerImage, "sh", "-c", initScript)
+	if output, err := initCmd.CombinedOutput(); err != nil {
+		_ = exec.Command("docker", "volume", "rm", "-f", volumeName).Run()
+		return nil, fmt.Errorf("%w: volume init failed: %v (output: %s)", ErrIsolationCreateFailed, err, strings.TrimSpace(string(output)))
+	}
+
+	return &IsolatedEnv{
+		ID:        workerID,
+		DataDir:   volumeName,
+		SitesFile: containerDataDir + "/sites.jsonc",
+		StatsFile: containerDataDir + "/stats.json",
+		LogsDir:   containerDataDir + "/logs",
+		Port:      port,
+		Kind:      "docker",
+	}, nil
+}
+
+func (dockerVolumeIsolation) Env(env *IsolatedEnv) []string {
+	// DATA_DIR here is the path inside the container where the Hono server
+	// should expect the volume mounted (see containerIsolator.Launch); the
+	// volume name itself travels separately so the process Isolator can
+	// build the "-v name:/data" mount flag.
 	return []string{
-		fmt.Sprintf("DATA_DIR=%s", e.DataDir),
-		fmt.Sprintf("PORT=%d", e.Port),
-		fmt.Sprintf("API_TOKEN=test-token-%d", e.ID),
+		"DATA_DIR=/data",
+		fmt.Sprintf("PORT=%d", env.Port),
+		fmt.Sprintf("API_TOKEN=test-token-%d", env.ID),
+		fmt.Sprintf("SS_DATA_VOLUME=%s", env.DataDir),
 	}
 }
 
-// URL returns the base URL for the worker's HTTP server.
-func (e *IsolatedEnv) URL() string {
-	return fmt.Sprintf("http://127.0.0.1:%d", e.Port)
+func (dockerVolumeIsolation) Cleanup(env *IsolatedEnv) error {
+	if output, err := exec.Command("docker", "volume", "rm", "-f", env.DataDir).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: docker volume rm failed: %v (output: %s)", ErrIsolationCleanupFailed, err, strings.TrimSpace(string(output)))
+	}
+	return nil
 }
+
+func (dockerVolumeIsolation) URL(env *IsolatedEnv) string { return urlFor(env) }
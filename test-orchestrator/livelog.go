@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// liveLogRingSize bounds how many recent bytes a LiveLog retains in memory
+// for readers that attach after some output has already been written.
+const liveLogRingSize = 64 * 1024
+
+// LiveLog fans a single write stream - normally a Worker's LogFile - out to
+// any number of concurrent readers, so several "curl"s can tail the same
+// worker's log at once. Writes pass through to the underlying io.Writer unchanged;
+// subscribers created via NewReader first replay the retained ring-buffer
+// tail (up to liveLogRingSize bytes written before they attached), then
+// every later byte, blocking for more until the log is closed.
+type LiveLog struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	out    io.Writer
+	ring   []byte // most recent liveLogRingSize bytes written
+	offset int64  // total bytes ever written; used as the subscriber cursor space
+	closed bool
+}
+
+// NewLiveLog wraps out so writes made through the returned LiveLog are also
+// replayed to any NewReader subscribers.
+func NewLiveLog(out io.Writer) *LiveLog {
+	ll := &LiveLog{out: out}
+	ll.cond = sync.NewCond(&ll.mu)
+	return ll
+}
+
+// Write implements io.Writer: p is passed through to the wrapped writer,
+// appended to the ring buffer, and any readers blocked in Read are woken.
+func (ll *LiveLog) Write(p []byte) (int, error) {
+	n, err := ll.out.Write(p)
+
+	ll.mu.Lock()
+	ll.ring = append(ll.ring, p[:n]...)
+	if len(ll.ring) > liveLogRingSize {
+		ll.ring = ll.ring[len(ll.ring)-liveLogRingSize:]
+	}
+	ll.offset += int64(n)
+	ll.cond.Broadcast()
+	ll.mu.Unlock()
+
+	return n, err
+}
+
+// Close marks the log finished, waking any readers blocked in Read so they
+// observe io.EOF instead of blocking forever.
+func (ll *LiveLog) Close() error {
+	ll.mu.Lock()
+	ll.closed = true
+	ll.cond.Broadcast()
+	ll.mu.Unlock()
+	return nil
+}
+
+// Snapshot returns a copy of the most recently retained bytes without
+// blocking, for callers that want a one-shot dump rather than a follow
+// cursor (e.g. the orchestrator's "?follow=0" log endpoint).
+func (ll *LiveLog) Snapshot() []byte {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+	out := make([]byte, len(ll.ring))
+	copy(out, ll.ring)
+	return out
+}
+
+// NewReader returns a follow-cursor over this log. The first Read returns
+// whatever tail of the ring buffer is still retained; subsequent Reads block
+// until new bytes are written, the LiveLog is closed, or the returned
+// io.ReadCloser's Close is called.
+func (ll *LiveLog) NewReader() io.ReadCloser {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ll.mu.Lock()
+	start := ll.offset - int64(len(ll.ring))
+	ll.mu.Unlock()
+
+	r := &liveLogReader{ll: ll, cursor: start, ctx: ctx, cancel: cancel}
+
+	// Cancelling ctx (via Close) only unblocks a Wait() once something
+	// broadcasts; this goroutine is that something.
+	go func() {
+		<-ctx.Done()
+		ll.mu.Lock()
+		ll.cond.Broadcast()
+		ll.mu.Unlock()
+	}()
+
+	return r
+}
+
+// liveLogReader is an io.ReadCloser cursor into a LiveLog.
+type liveLogReader struct {
+	ll     *LiveLog
+	cursor int64 // next absolute offset to read from
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *liveLogReader) Read(p []byte) (int, error) {
+	ll := r.ll
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+
+	for {
+		ringStart := ll.offset - int64(len(ll.ring))
+		if r.cursor < ringStart {
+			// The reader fell behind and lost some data to ring eviction;
+			// resync to the oldest byte still retained rather than erroring.
+			r.cursor = ringStart
+		}
+
+		if r.cursor < ll.offset {
+			n := copy(p, ll.ring[r.cursor-ringStart:])
+			r.cursor += int64(n)
+			return n, nil
+		}
+
+		if ll.closed {
+			return 0, io.EOF
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		default:
+		}
+
+		ll.cond.Wait()
+	}
+}
+
+// Close detaches this reader, unblocking any in-flight Read with ctx.Err().
+func (r *liveLogReader) Close() error {
+	r.cancel()
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LogEntry mirrors the structured scrape-log record each worker's Hono
+// server appends to DATA_DIR/logs/YYYY-MM-DD.jsonl (see e2e.LogEntry for the
+// test-side counterpart). Worker identifies which worker produced it, so
+// entries tailed from many workers can be queried together.
+type LogEntry struct {
+	Worker    int    `json:"worker"`
+	Ts        string `json:"ts"`
+	Domain    string `json:"domain"`
+	URL       string `json:"url"`
+	Success   bool   `json:"success"`
+	Method    string `json:"method,omitempty"`
+	Xpath     string `json:"xpath,omitempty"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Ms        int    `json:"ms"`
+}
+
+// LogFilter narrows a Collector.Query. Every field matches anything at its
+// zero value, except Worker (-1, since worker IDs start at 0).
+type LogFilter struct {
+	Worker    int       // -1 matches any worker
+	Domain    string    // "" matches any domain
+	Success   *bool     // nil matches both outcomes
+	ErrorType string    // "" matches any errorType
+	Since     time.Time // zero value matches any timestamp
+}
+
+// logTailScanInterval is how often LogCollector re-checks each worker's logs
+// directory for new complete lines.
+const logTailScanInterval = 500 * time.Millisecond
+
+// LogCollector tails every registered worker's DATA_DIR/logs/*.jsonl files in
+// real time, decoding LogEntry records as they're appended, and keeps them
+// in memory so a query can span all workers without re-reading any file.
+// Entries are held in one list in ingestion order; there is no per-field
+// index, so each Query scans every retained entry. Safe for concurrent use.
+type LogCollector struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+// NewLogCollector creates an empty collector.
+func NewLogCollector() *LogCollector {
+	return &LogCollector{}
+}
+
+// Watch starts tailing worker's isolated logs directory in the background
+// until ctx is done. Safe to call once per worker; scans are skipped while
+// the worker has no isolated environment, and tailing starts once it has one.
+func (c *LogCollector) Watch(ctx context.Context, worker *Worker) {
+	go c.tailLoop(ctx, worker)
+}
+
+// tailLoop polls worker's logs directory for new *.jsonl content every
+// logTailScanInterval, tracking how many bytes of each file have already
+// been consumed.
+func (c *LogCollector) tailLoop(ctx context.Context, worker *Worker) {
+	ticker := time.NewTicker(logTailScanInterval)
+	defer ticker.Stop()
+
+	offsets := make(map[string]int64)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.scanOnce(worker, offsets)
+		}
+	}
+}
+
+func (c *LogCollector) scanOnce(worker *Worker, offsets map[string]int64) {
+	if worker.IsolatedEnv == nil {
+		return
+	}
+
+	matches, err := filepath.Glob(filepath.Join(worker.IsolatedEnv.LogsDir, "*.jsonl"))
+	if err != nil {
+		return
+	}
+
+	for _, path := range matches {
+		c.scanFile(worker.ID, path, offsets)
+	}
+}
+
+// scanFile appends any newly-completed JSON lines in path to the collector,
+// advancing offsets[path] past only the lines that ended in a newline - a
+// line still being written is picked up on a later scan instead of being
+// parsed half-written.
+func (c *LogCollector) scanFile(workerID int, path string, offsets map[string]int64) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	offset := offsets[path]
+	if offset > int64(len(data)) {
+		offset = 0 // file was truncated or replaced; start over
+	}
+	tail := data[offset:]
+
+	lastNewline := bytes.LastIndexByte(tail, '\n')
+	if lastNewline < 0 {
+		return
+	}
+	complete := tail[:lastNewline+1]
+	offsets[path] = offset + int64(len(complete))
+
+	var newEntries []LogEntry
+	for _, line := range bytes.Split(complete, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry LogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entry.Worker = workerID
+		newEntries = append(newEntries, entry)
+	}
+
+	if len(newEntries) > 0 {
+		c.mu.Lock()
+		c.entries = append(c.entries, newEntries...)
+		c.mu.Unlock()
+	}
+}
+
+// Query returns every collected entry matching filter, in the order it was
+// ingested.
+func (c *LogCollector) Query(filter LogFilter) []LogEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []LogEntry
+	for _, e := range c.entries {
+		if filter.Worker >= 0 && e.Worker != filter.Worker {
+			continue
+		}
+		if filter.Domain != "" && e.Domain != filter.Domain {
+			continue
+		}
+		if filter.Success != nil && e.Success != *filter.Success {
+			continue
+		}
+		if filter.ErrorType != "" && e.ErrorType != filter.ErrorType {
+			continue
+		}
+		if !filter.Since.IsZero() {
+			ts, err := time.Parse(time.RFC3339, e.Ts)
+			if err == nil && ts.Before(filter.Since) {
+				continue
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
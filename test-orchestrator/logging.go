@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// NewLogger builds the orchestrator's structured logger. format selects
+// "text" (human-readable, the default) or "json" (line-delimited JSON for a
+// downstream log aggregator) output on stdout. Every record carries a
+// "run_id" field so orchestrator events can be joined against the
+// corresponding Hono server logs captured under logs-dir - the same run ID
+// is propagated to worker processes via the SS_RUN_ID environment variable.
+func NewLogger(format string, runID string) *slog.Logger {
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, nil)
+	default:
+		handler = slog.NewTextHandler(os.Stdout, nil)
+	}
+
+	return slog.New(handler).With("run_id", runID)
+}
+
+// newRunID generates a correlation ID for this orchestrator invocation,
+// honoring RUN_ID from the environment so a CI system can inject its own
+// build/job ID instead of a freshly generated one.
+func newRunID() string {
+	if id := os.Getenv("RUN_ID"); id != "" {
+		return id
+	}
+	return fmt.Sprintf("run-%d-%d", time.Now().UnixNano(), os.Getpid())
+}
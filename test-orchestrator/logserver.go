@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewLogHandler returns an http.Handler exposing each worker's live log over
+// HTTP, so a developer can run:
+//
+//	curl 'http://127.0.0.1:<debug-addr-port>/workers/2/logs?follow=1'
+//
+// The live log carries what the orchestrator writes to the worker's log
+// file (see Worker.NewLogReader); the Hono server's own stdout/stderr is not
+// part of it. follow=1 (the default)
+// streams new output as it's written until the client disconnects or the
+// worker stops; follow=0 returns a single snapshot of the retained buffer.
+func NewLogHandler(pool *WorkerPool) http.Handler {
+	return &logHandler{pool: pool}
+}
+
+type logHandler struct {
+	pool *WorkerPool
+}
+
+func (h *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWorkerLogPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var worker *Worker
+	for _, candidate := range h.pool.Workers() {
+		if candidate.ID == id {
+			worker = candidate
+			break
+		}
+	}
+	if worker == nil {
+		http.Error(w, fmt.Sprintf("unknown worker %d", id), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if r.URL.Query().Get("follow") == "0" {
+		w.Write(worker.liveLog.Snapshot())
+		return
+	}
+
+	reader, err := worker.NewLogReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer reader.Close()
+
+	// reader.Read below blocks until the worker writes more output, its live
+	// log is closed, or the reader is closed. This goroutine closes the
+	// reader once the client disconnects. Without it, a disconnected client
+	// with a quiet worker would leak the request goroutine forever in
+	// reader.Read's cond.Wait().
+	go func() {
+		<-r.Context().Done()
+		reader.Close()
+	}()
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 4096)
+	for {
+		n, readErr := reader.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if readErr != nil {
+			// io.EOF (worker stopped) or the request's context being
+			// canceled (client disconnected) both just end the stream.
+			return
+		}
+	}
+}
+
+// NewLogQueryHandler returns an http.Handler exposing the collector's
+// structured scrape logs across every worker as newline-delimited JSON, e.g.:
+//
+//	curl 'http://127.0.0.1:<debug-addr-port>/logs?domain=httpbin.org&success=false'
+//
+// Recognized query parameters are worker, domain, success, errorType, and
+// since (RFC3339); any omitted parameter matches everything.
+func NewLogQueryHandler(collector *LogCollector) http.Handler {
+	return &logQueryHandler{collector: collector}
+}
+
+type logQueryHandler struct {
+	collector *LogCollector
+}
+
+func (h *logQueryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter := LogFilter{Worker: -1}
+	q := r.URL.Query()
+
+	if v := q.Get("worker"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid worker %q", v), http.StatusBadRequest)
+			return
+		}
+		filter.Worker = id
+	}
+
+	filter.Domain = q.Get("domain")
+	filter.ErrorType = q.Get("errorType")
+
+	if v := q.Get("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid success %q", v), http.StatusBadRequest)
+			return
+		}
+		filter.Success = &success
+	}
+
+	if v := q.Get("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid since %q, want RFC3339", v), http.StatusBadRequest)
+			return
+		}
+		filter.Since = since
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	for _, entry := range h.collector.Query(filter) {
+		if err := enc.Encode(entry); err != nil {
+			return
+		}
+	}
+}
+
+// parseWorkerLogPath extracts the worker ID from a "/workers/{id}/logs" path.
+func parseWorkerLogPath(path string) (int, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "workers" || parts[2] != "logs" {
+		return 0, false
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
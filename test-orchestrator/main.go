@@ -12,6 +12,23 @@
 //	    --timeout duration Health check timeout (default 60s)
 //	    --logs-dir string  Log directory (default "test-orchestrator/logs")
 //	-v, --verbose          Enable verbose output
+//	    --retries int      Re-run a failed test on a restarted worker up to N times (default 0)
+//	    --include-quarantined  Run tests listed in .test-quarantine instead of skipping them
+//	    --test-timeout duration  Soft per-test deadline (default 5m), overridable via "// timetrap:" pragma
+//	    --lame-duck-timeout duration  Drain time for in-flight tests before force-killing workers (default 15s)
+//	    --llm-rate-per-sec float      Max OpenRouter requests/sec for "// uses: openrouter" tests (default 2.0)
+//	    --captcha-rate-per-sec float  Max 2Captcha requests/sec for "// uses: twocaptcha" tests (default 0.5)
+//	    --report kind:path            Write a machine-readable report; repeatable. kind is "jsonl", "json", or "junit"
+//	    --junit-out path              Shorthand for --report=junit:path
+//	    --json-out path               Shorthand for --report=json:path (summary incl. worker id, DATA_DIR, cache-hit, output excerpt)
+//	    --shard i/N                   Run only the i-th of N deterministic shards of the discovered tests (e.g. "0/4")
+//	    --resume-from path            Skip tests already completed in this file from a prior, interrupted run
+//	    --isolation string            Worker process launch backend: "tmux" (default), "docker", or "podman"
+//	    --isolation-kind string       Worker DATA_DIR provisioning backend: "tmpdir" (default), "tmpfs", or "docker"
+//	    --port-range start-end        Range workers may be assigned ports from (default "9000-9099"); leases persist under logs-dir so concurrent runs on one host don't collide
+//	    --app-probe-url string        Known-good fixture URL (e.g. "https://httpbin.org/html") to POST to /api/scrape as a third WaitReady gate proving the server can serve a real request, not just listen; "" (default) skips this gate
+//	    --log-format string           Structured log output: "text" (default) or "json"; carries a run_id correlation field, also propagated to workers via SS_RUN_ID
+//	    --debug-addr string           If set (e.g. "127.0.0.1:9500"), serve live worker logs at GET /workers/{id}/logs?follow=1 and structured scrape logs at GET /logs
 //
 // Examples:
 //
@@ -28,13 +45,28 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 )
 
+// reportSpecs collects repeated -report kind:path flags, e.g.
+// "-report=jsonl:out.jsonl -report=junit:out.xml".
+type reportSpecs []string
+
+func (r *reportSpecs) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *reportSpecs) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
 func main() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -43,9 +75,6 @@ func main() {
 		}
 	}()
 
-	// Log startup
-	fmt.Printf("[%s] SmartScraper Test Orchestrator starting...\n", time.Now().Format("15:04:05.000"))
-
 	// Change to project root if we're in test-orchestrator/
 	if err := ensureProjectRoot(); err != nil {
 		fmt.Fprintf(os.Stderr, "[%s] ERROR: %v\n", time.Now().Format("15:04:05.000"), err)
@@ -55,6 +84,13 @@ func main() {
 	// Parse command line flags
 	config := parseFlags()
 
+	// Structured logging: every record from here on carries a run_id
+	// correlation field, also propagated to worker processes via SS_RUN_ID,
+	// so orchestrator events can be joined against the corresponding Hono
+	// server logs captured under logs-dir.
+	slog.SetDefault(NewLogger(config.LogFormat, config.RunID))
+	slog.Info("SmartScraper Test Orchestrator starting", "run_id", config.RunID)
+
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -65,16 +101,16 @@ func main() {
 
 	go func() {
 		sig := <-signalChan
-		fmt.Printf("\n[%s] Received signal %v, initiating shutdown...\n", time.Now().Format("15:04:05.000"), sig)
+		slog.Warn("received signal, initiating shutdown", "signal", sig.String())
 		cancel()
 
 		// Give cleanup 30 seconds before force exit
 		select {
 		case <-time.After(30 * time.Second):
-			fmt.Fprintf(os.Stderr, "[%s] FATAL: Cleanup timed out after 30s, forcing exit\n", time.Now().Format("15:04:05.000"))
+			slog.Error("cleanup timed out after 30s, forcing exit")
 			os.Exit(2)
 		case sig := <-signalChan:
-			fmt.Fprintf(os.Stderr, "[%s] FATAL: Received second signal %v, forcing exit\n", time.Now().Format("15:04:05.000"), sig)
+			slog.Error("received second signal, forcing exit", "signal", sig.String())
 			os.Exit(2)
 		}
 	}()
@@ -82,15 +118,15 @@ func main() {
 	// Create orchestrator
 	orchestrator, err := NewOrchestrator(config)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "[%s] ERROR: Failed to create orchestrator: %v\n", time.Now().Format("15:04:05.000"), err)
+		slog.Error("failed to create orchestrator", "error", err)
 		os.Exit(1)
 	}
 
 	// Ensure cleanup always runs
 	defer func() {
-		fmt.Printf("[%s] Running cleanup...\n", time.Now().Format("15:04:05.000"))
+		slog.Info("running cleanup")
 		if cleanupErr := orchestrator.Cleanup(); cleanupErr != nil {
-			fmt.Fprintf(os.Stderr, "[%s] WARNING: Cleanup error: %v\n", time.Now().Format("15:04:05.000"), cleanupErr)
+			slog.Warn("cleanup error", "error", cleanupErr)
 		}
 	}()
 
@@ -98,25 +134,28 @@ func main() {
 	exitCode := 0
 	if err := orchestrator.Run(ctx); err != nil {
 		if err == ErrNoTestsFound {
-			fmt.Fprintf(os.Stderr, "[%s] ERROR: No test files found in test-orchestrator/e2e/\n", time.Now().Format("15:04:05.000"))
+			slog.Error("no test files found in test-orchestrator/e2e/")
 			exitCode = 1
 		} else if err == ErrTestsFailed {
 			// Test failures already printed, exit with failure code
 			exitCode = 1
 		} else if ctx.Err() == context.Canceled {
-			fmt.Printf("[%s] Orchestrator interrupted\n", time.Now().Format("15:04:05.000"))
+			slog.Info("orchestrator interrupted")
 			exitCode = 130 // Standard exit code for SIGINT
 		} else {
-			fmt.Fprintf(os.Stderr, "[%s] ERROR: %v\n", time.Now().Format("15:04:05.000"), err)
+			slog.Error("orchestrator run failed", "error", err)
 			exitCode = 1
 		}
 	}
 
-	fmt.Printf("[%s] Done\n", time.Now().Format("15:04:05.000"))
+	slog.Info("done", "exit_code", exitCode)
 	os.Exit(exitCode)
 }
 
-// parseFlags parses command line flags and returns a Config.
+// parseFlags parses command line flags and returns a Config. Its diagnostics
+// are plain fmt to stderr rather than slog: --log-format itself is one of
+// the flags being parsed, so slog.SetDefault can't be called until after
+// this function returns.
 func parseFlags() *Config {
 	config := &Config{}
 
@@ -136,8 +175,85 @@ func parseFlags() *Config {
 	flag.BoolVar(&config.Verbose, "verbose", false, "Enable verbose output")
 	flag.BoolVar(&config.Verbose, "v", false, "Enable verbose output (shorthand for --verbose)")
 
+	flag.IntVar(&config.Retries, "retries", 0, "Re-run a failed test on a freshly-restarted worker up to N times before marking it failed")
+	flag.BoolVar(&config.IncludeQuarantined, "include-quarantined", false, "Run tests listed in "+QuarantineFile+" instead of auto-skipping them")
+
+	flag.DurationVar(&config.TestTimeout, "test-timeout", 5*time.Minute, "Soft per-test deadline; overridden per-test by a \"// timetrap: 30s\" pragma")
+
+	flag.DurationVar(&config.LameDuckTimeout, "lame-duck-timeout", 15*time.Second, "How long to wait for in-flight tests to finish before force-killing workers on shutdown")
+
+	flag.Float64Var(&config.LLMRatePerSec, "llm-rate-per-sec", 2.0, "Max OpenRouter requests/sec across all workers, for tests marked \"// uses: openrouter\"")
+	flag.Float64Var(&config.CaptchaRatePerSec, "captcha-rate-per-sec", 0.5, "Max 2Captcha requests/sec across all workers, for tests marked \"// uses: twocaptcha\"")
+
+	var reports reportSpecs
+	flag.Var(&reports, "report", "Write a machine-readable report, kind:path (kind is \"jsonl\", \"json\", or \"junit\"); may be repeated")
+
+	var junitOut, jsonOut string
+	flag.StringVar(&junitOut, "junit-out", "", "Shorthand for --report=junit:path")
+	flag.StringVar(&jsonOut, "json-out", "", "Shorthand for --report=json:path (per-test duration, worker id, DATA_DIR, cache-hit, output excerpt)")
+
+	var shardSpec string
+	flag.StringVar(&shardSpec, "shard", "", "Run only shard i of N deterministic shards of the discovered tests, e.g. \"0/4\"")
+
+	flag.StringVar(&config.ResumeFrom, "resume-from", "", "Skip tests already completed in this file from a prior, interrupted run")
+
+	flag.StringVar(&config.Isolation, "isolation", "tmux", "Worker process launch backend: \"tmux\", \"docker\", or \"podman\"")
+
+	flag.StringVar(&config.IsolationKind, "isolation-kind", "tmpdir", "Worker DATA_DIR provisioning backend: \"tmpdir\", \"tmpfs\", or \"docker\"")
+
+	var portRange string
+	flag.StringVar(&portRange, "port-range", "9000-9099", "Range of ports workers may be assigned from, e.g. \"9000-9099\"")
+
+	flag.StringVar(&config.AppProbeURL, "app-probe-url", "", "Known-good fixture URL to POST to /api/scrape as a third WaitReady readiness gate; \"\" skips it")
+
+	flag.StringVar(&config.LogFormat, "log-format", "text", "Structured log output: \"text\" or \"json\"")
+
+	flag.StringVar(&config.DebugAddr, "debug-addr", "", "If set, serve live worker logs at GET /workers/{id}/logs?follow=1 on this address")
+
 	flag.Parse()
 
+	if config.LogFormat != "text" && config.LogFormat != "json" {
+		fmt.Fprintf(os.Stderr, "[%s] ERROR: invalid --log-format %q (want \"text\" or \"json\")\n", time.Now().Format("15:04:05.000"), config.LogFormat)
+		os.Exit(1)
+	}
+	config.RunID = newRunID()
+
+	config.Reports = []string(reports)
+	if junitOut != "" {
+		config.Reports = append(config.Reports, "junit:"+junitOut)
+	}
+	if jsonOut != "" {
+		config.Reports = append(config.Reports, "json:"+jsonOut)
+	}
+
+	if _, err := NewIsolator(config.Isolation); err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] ERROR: %v\n", time.Now().Format("15:04:05.000"), err)
+		os.Exit(1)
+	}
+
+	if _, err := NewIsolation(config.IsolationKind); err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] ERROR: %v\n", time.Now().Format("15:04:05.000"), err)
+		os.Exit(1)
+	}
+
+	start, end, err := ParsePortRange(portRange)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] ERROR: %v\n", time.Now().Format("15:04:05.000"), err)
+		os.Exit(1)
+	}
+	config.PortRangeStart = start
+	config.PortRangeEnd = end
+
+	if shardSpec != "" {
+		shard, err := ParseShardSpec(shardSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] ERROR: %v\n", time.Now().Format("15:04:05.000"), err)
+			os.Exit(1)
+		}
+		config.ShardIndex = shard.Index
+		config.ShardTotal = shard.Total
+	}
+
 	// Validate and clamp workers to 1-8 range
 	if config.MaxWorkers < 1 {
 		config.MaxWorkers = 1
@@ -150,13 +266,31 @@ func parseFlags() *Config {
 
 	// Log configuration if verbose
 	if config.Verbose {
-		fmt.Printf("[%s] Config: workers=%d, pattern=%q, full=%v, timeout=%v, logs-dir=%q\n",
+		fmt.Printf("[%s] Config: workers=%d, pattern=%q, full=%v, timeout=%v, logs-dir=%q, retries=%d, include-quarantined=%v, test-timeout=%v, lame-duck-timeout=%v, llm-rate=%.2f/s, captcha-rate=%.2f/s, reports=%v, shard=%d/%d, resume-from=%q, isolation=%q, isolation-kind=%q, port-range=%d-%d, app-probe-url=%q, log-format=%q, run-id=%q, debug-addr=%q\n",
 			time.Now().Format("15:04:05.000"),
 			config.MaxWorkers,
 			config.Pattern,
 			config.ForceFull,
 			config.HealthTimeout,
 			config.LogsDir,
+			config.Retries,
+			config.IncludeQuarantined,
+			config.TestTimeout,
+			config.LameDuckTimeout,
+			config.LLMRatePerSec,
+			config.CaptchaRatePerSec,
+			config.Reports,
+			config.ShardIndex,
+			config.ShardTotal,
+			config.ResumeFrom,
+			config.Isolation,
+			config.IsolationKind,
+			config.PortRangeStart,
+			config.PortRangeEnd,
+			config.AppProbeURL,
+			config.LogFormat,
+			config.RunID,
+			config.DebugAddr,
 		)
 	}
 
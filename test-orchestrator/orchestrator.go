@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,28 +19,55 @@ import (
 
 // Config holds configuration for the test orchestrator.
 type Config struct {
-	MaxWorkers    int
-	Pattern       string
-	ForceFull     bool
-	LogsDir       string
-	HealthTimeout time.Duration
-	Verbose       bool
+	MaxWorkers         int
+	Pattern            string
+	ForceFull          bool
+	LogsDir            string
+	HealthTimeout      time.Duration
+	Verbose            bool
+	Retries            int
+	IncludeQuarantined bool
+	TestTimeout        time.Duration
+	LameDuckTimeout    time.Duration
+	LLMRatePerSec      float64
+	CaptchaRatePerSec  float64
+	Reports            []string // e.g. "jsonl:out.jsonl", "junit:out.xml"
+	ShardIndex         int      // from "--shard i/N", 0 if unset
+	ShardTotal         int      // from "--shard i/N", 0 if unset
+	ResumeFrom         string   // path to a resume-state file, "" to disable
+	Isolation          string   // "tmux" (default), "docker", or "podman" - see Isolator
+	IsolationKind      string   // "tmpdir" (default), "tmpfs", or "docker" - see Isolation
+	PortRangeStart     int      // first port workers may be assigned, see PortAllocator
+	PortRangeEnd       int      // last port workers may be assigned, see PortAllocator
+	AppProbeURL        string   // fixture URL for WaitReady's optional app-scrape gate; "" skips it
+	LogFormat          string   // "text" (default) or "json" - see NewLogger
+	RunID              string   // correlation ID for this invocation, set post-parse in parseFlags
+	DebugAddr          string   // e.g. "127.0.0.1:9500"; "" disables the live log HTTP endpoint
 }
 
 // TestInfo holds metadata about a single test function.
 type TestInfo struct {
-	FuncName string // e.g., "TestHealthEndpoint"
-	FilePath string // e.g., "test-orchestrator/e2e/basic_test.go"
+	FuncName    string        // e.g., "TestHealthEndpoint"
+	FilePath    string        // e.g., "test-orchestrator/e2e/basic_test.go"
+	SoftTimeout time.Duration // from a "// timetrap: 30s" pragma, 0 if none
+	Uses        []string      // from a "// uses: openrouter,twocaptcha" pragma
 }
 
 // Orchestrator coordinates test discovery, worker management, and parallel execution.
 type Orchestrator struct {
-	config       *Config
-	secrets      *Secrets
-	pool         *WorkerPool
-	cache        *TestCache
-	cleanupFuncs []func() error
-	mu           sync.Mutex
+	config         *Config
+	secrets        *Secrets
+	pool           *WorkerPool
+	cache          *TestCache
+	quarantine     map[string]bool
+	flaky          *FlakyReport
+	llmLimiter     *AdaptiveLimiter
+	captchaLimiter *AdaptiveLimiter
+	reports        *ReportCollector
+	resume         *ResumeState // nil unless Config.ResumeFrom is set
+	logs           *LogCollector
+	cleanupFuncs   []func() error
+	mu             sync.Mutex
 }
 
 // NewOrchestrator creates a new orchestrator with the given configuration.
@@ -62,18 +91,43 @@ func NewOrchestrator(config *Config) (*Orchestrator, error) {
 
 	// Check if helpers changed - if so, invalidate entire cache
 	if cache.CheckHelpersChanged() && config.Verbose {
-		fmt.Println("[orchestrator] helpers.go changed - all tests will run")
+		slog.Info("helpers.go changed, all tests will run")
+	}
+
+	// Load the quarantine list of known-flaky tests to auto-skip
+	quarantine, err := LoadQuarantine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quarantine list: %w", err)
 	}
 
 	// Create worker pool
-	pool := NewWorkerPool(config.MaxWorkers, config.LogsDir)
+	pool, err := NewWorkerPool(config.MaxWorkers, config.LogsDir, config.Isolation, config.IsolationKind, config.RunID, config.PortRangeStart, config.PortRangeEnd, config.AppProbeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worker pool: %w", err)
+	}
+
+	// Load resume state, if the caller asked for one.
+	var resume *ResumeState
+	if config.ResumeFrom != "" {
+		resume, err = LoadResumeState(config.ResumeFrom)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load resume state: %w", err)
+		}
+	}
 
 	return &Orchestrator{
-		config:       config,
-		secrets:      secrets,
-		pool:         pool,
-		cache:        cache,
-		cleanupFuncs: make([]func() error, 0),
+		config:         config,
+		secrets:        secrets,
+		pool:           pool,
+		cache:          cache,
+		quarantine:     quarantine,
+		flaky:          NewFlakyReport(),
+		llmLimiter:     NewAdaptiveLimiter("openrouter", config.LLMRatePerSec, RateLimitCooldown),
+		captchaLimiter: NewAdaptiveLimiter("twocaptcha", config.CaptchaRatePerSec, RateLimitCooldown),
+		reports:        NewReportCollector(),
+		resume:         resume,
+		logs:           NewLogCollector(),
+		cleanupFuncs:   make([]func() error, 0),
 	}, nil
 }
 
@@ -81,7 +135,7 @@ func NewOrchestrator(config *Config) (*Orchestrator, error) {
 func (o *Orchestrator) Run(ctx context.Context) error {
 	// Phase 1: Discover test functions
 	if o.config.Verbose {
-		fmt.Println("[orchestrator] Phase 1: Discovering tests...")
+		slog.Info("discovering tests")
 	}
 
 	tests, err := o.discoverTestFunctions()
@@ -94,18 +148,31 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 	}
 
 	if o.config.Verbose {
-		fmt.Printf("[orchestrator] Found %d test function(s)\n", len(tests))
+		slog.Info("found test functions", "count", len(tests))
 	}
 
 	// Phase 2: Filter by pattern if specified
 	if o.config.Pattern != "" {
 		tests = o.filterTestFunctions(tests, o.config.Pattern)
 		if len(tests) == 0 {
-			fmt.Printf("No tests match pattern %q\n", o.config.Pattern)
+			slog.Info("no tests match pattern", "pattern", o.config.Pattern)
 			return nil
 		}
 		if o.config.Verbose {
-			fmt.Printf("[orchestrator] %d test(s) match pattern %q\n", len(tests), o.config.Pattern)
+			slog.Info("tests matched pattern", "count", len(tests), "pattern", o.config.Pattern)
+		}
+	}
+
+	// Phase 2b: Filter by shard if --shard i/N was given, so distributed CI
+	// runners each own a deterministic, disjoint slice of the test set.
+	if o.config.ShardTotal > 0 {
+		shard := &ShardSpec{Index: o.config.ShardIndex, Total: o.config.ShardTotal}
+		tests = filterByShard(tests, shard)
+		if len(tests) == 0 {
+			return ErrShardEmpty
+		}
+		if o.config.Verbose {
+			slog.Info("shard owns tests", "shard_index", shard.Index, "shard_total", shard.Total, "count", len(tests))
 		}
 	}
 
@@ -114,23 +181,69 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 	if o.config.ForceFull {
 		toRun = tests
 		if o.config.Verbose {
-			fmt.Println("[orchestrator] Force full run - skipping cache check")
+			slog.Info("force full run, skipping cache check")
 		}
 	} else {
 		toRun, skipped = o.filterByCache(tests)
 		if o.config.Verbose {
-			fmt.Printf("[orchestrator] %d test(s) to run, %d cached/skipped\n", len(toRun), len(skipped))
+			slog.Info("cache filter applied", "to_run", len(toRun), "cached", len(skipped))
+		}
+		for _, test := range skipped {
+			o.reports.Add(&TestCaseResult{Name: test.FuncName, Action: "pass", CacheHit: true})
+		}
+	}
+
+	// Phase 3b: Filter out quarantined tests unless explicitly included
+	if !o.config.IncludeQuarantined && len(o.quarantine) > 0 {
+		var kept []TestInfo
+		quarantinedCount := 0
+		for _, test := range toRun {
+			if o.quarantine[test.FuncName] {
+				quarantinedCount++
+				if o.config.Verbose {
+					slog.Info("skipping quarantined test", "test_name", test.FuncName, "quarantine_file", QuarantineFile)
+				}
+				continue
+			}
+			kept = append(kept, test)
+		}
+		toRun = kept
+		if quarantinedCount > 0 {
+			slog.Info("skipped quarantined tests", "count", quarantinedCount)
+		}
+	}
+
+	// Phase 3c: Filter out tests already completed by a prior, interrupted
+	// run, per --resume-from.
+	if o.resume != nil {
+		var kept []TestInfo
+		resumedCount := 0
+		for _, test := range toRun {
+			if o.resume.NeedsRun(test.FilePath) {
+				kept = append(kept, test)
+				continue
+			}
+			resumedCount++
+			if o.config.Verbose {
+				slog.Info("skipping already-completed test", "test_name", test.FuncName)
+			}
+			o.reports.Add(&TestCaseResult{Name: test.FuncName, Action: "pass", CacheHit: true})
+			skipped = append(skipped, test)
+		}
+		toRun = kept
+		if resumedCount > 0 {
+			slog.Info("skipped already-completed tests", "count", resumedCount, "resume_from", o.config.ResumeFrom)
 		}
 	}
 
 	if len(toRun) == 0 {
-		fmt.Printf("All %d test(s) cached - nothing to run\n", len(skipped))
+		slog.Info("all tests cached, nothing to run", "count", len(skipped))
 		return nil
 	}
 
 	// Phase 4: Start worker pool
 	if o.config.Verbose {
-		fmt.Println("[orchestrator] Phase 4: Starting worker pool...")
+		slog.Info("starting worker pool")
 	}
 
 	if err := o.pool.Start(ctx, o.config.HealthTimeout, o.config.Verbose); err != nil {
@@ -139,15 +252,59 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 
 	// Register cleanup for worker pool
 	o.RegisterCleanup(func() error {
-		return o.pool.Shutdown(ctx)
+		return o.pool.Shutdown(ctx, o.config.LameDuckTimeout)
 	})
 
+	// Tail every worker's structured scrape logs into o.logs so they can be
+	// queried across workers without re-reading DATA_DIR/logs files.
+	for _, worker := range o.pool.Workers() {
+		o.logs.Watch(ctx, worker)
+	}
+
+	// Optionally serve live worker logs and structured log queries over
+	// HTTP, e.g. curl 'http://127.0.0.1:9500/workers/0/logs?follow=1' or
+	// curl 'http://127.0.0.1:9500/logs?domain=httpbin.org&success=false'
+	if o.config.DebugAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/workers/", NewLogHandler(o.pool))
+		mux.Handle("/logs", NewLogQueryHandler(o.logs))
+		debugServer := &http.Server{Addr: o.config.DebugAddr, Handler: mux}
+		go func() {
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Warn("debug log server stopped", "error", err)
+			}
+		}()
+		if o.config.Verbose {
+			slog.Info("debug endpoints available", "addr", o.config.DebugAddr)
+		}
+		o.RegisterCleanup(func() error {
+			return debugServer.Close()
+		})
+	}
+
 	// Phase 5: Run tests in parallel
 	if o.config.Verbose {
-		fmt.Println("[orchestrator] Phase 5: Running tests in parallel...")
+		slog.Info("running tests in parallel")
+	}
+
+	runErr := o.runParallelTests(ctx, toRun, len(skipped))
+
+	// Persist the flaky report regardless of outcome so users can inspect
+	// which tests needed a retry to pass.
+	if flakyErr := o.flaky.Save(FlakyFile); flakyErr != nil && o.config.Verbose {
+		slog.Warn("failed to write flaky report", "path", FlakyFile, "error", flakyErr)
 	}
 
-	return o.runParallelTests(ctx, toRun, len(skipped))
+	// Write any configured --report=jsonl:path / --report=junit:path sinks.
+	if len(o.config.Reports) > 0 {
+		if reportErr := o.reports.Flush(o.config.Reports); reportErr != nil {
+			slog.Warn("failed to flush reports", "error", reportErr)
+		} else if o.config.Verbose {
+			slog.Info("wrote reports", "reports", strings.Join(o.config.Reports, ", "))
+		}
+	}
+
+	return runErr
 }
 
 // discoverTestFunctions finds all test functions in e2e test files.
@@ -176,15 +333,50 @@ func (o *Orchestrator) discoverTestFunctions() ([]TestInfo, error) {
 		}
 
 		scanner := bufio.NewScanner(file)
+		var pendingComments []string
 		for scanner.Scan() {
 			line := scanner.Text()
+			trimmed := strings.TrimSpace(line)
+
 			matches := testFuncRegex.FindStringSubmatch(line)
 			if len(matches) >= 2 {
 				funcName := matches[1]
+
+				// Second pass: scan the contiguous run of "//" comment lines
+				// directly above for magic pragmas, e.g. "// timetrap: 30s"
+				// (overrides Config.TestTimeout) and "// uses: openrouter"
+				// (rate-limited upstream services this test calls).
+				var softTimeout time.Duration
+				var uses []string
+				for _, comment := range pendingComments {
+					if pragma := timetrapRegex.FindStringSubmatch(comment); len(pragma) >= 2 {
+						if d, err := time.ParseDuration(pragma[1]); err == nil {
+							softTimeout = d
+						}
+					}
+					if pragma := usesRegex.FindStringSubmatch(comment); len(pragma) >= 2 {
+						for _, svc := range strings.Split(pragma[1], ",") {
+							if svc = strings.TrimSpace(svc); svc != "" {
+								uses = append(uses, svc)
+							}
+						}
+					}
+				}
+
 				tests = append(tests, TestInfo{
-					FuncName: funcName,
-					FilePath: filePath,
+					FuncName:    funcName,
+					FilePath:    filePath,
+					SoftTimeout: softTimeout,
+					Uses:        uses,
 				})
+				pendingComments = nil
+				continue
+			}
+
+			if strings.HasPrefix(trimmed, "//") {
+				pendingComments = append(pendingComments, trimmed)
+			} else if trimmed != "" {
+				pendingComments = nil
 			}
 		}
 
@@ -218,12 +410,12 @@ func (o *Orchestrator) filterByCache(tests []TestInfo) (toRun, skipped []TestInf
 		needsRun, reason := o.cache.NeedsRunWithReason(test.FilePath)
 		if needsRun {
 			if o.config.Verbose {
-				fmt.Printf("[cache] %s: %s\n", test.FuncName, reason)
+				slog.Info("cache decision", "test_name", test.FuncName, "reason", reason)
 			}
 			toRun = append(toRun, test)
 		} else {
 			if o.config.Verbose {
-				fmt.Printf("[cache] %s: %s (skipped)\n", test.FuncName, reason)
+				slog.Info("cache decision", "test_name", test.FuncName, "reason", reason, "skipped", true)
 			}
 			skipped = append(skipped, test)
 		}
@@ -294,9 +486,7 @@ func (o *Orchestrator) runParallelTests(ctx context.Context, tests []TestInfo, c
 
 	// Print summary
 	total := passed + failed + skipped
-	fmt.Println()
-	fmt.Printf("Test Summary: %d passed, %d failed, %d skipped (total: %d)\n",
-		passed, failed, skipped, total)
+	slog.Info("test summary", "passed", passed, "failed", failed, "skipped", skipped, "total", total)
 
 	if failed > 0 {
 		return ErrTestsFailed
@@ -306,7 +496,127 @@ func (o *Orchestrator) runParallelTests(ctx context.Context, tests []TestInfo, c
 }
 
 // runSingleTest executes a single test function and returns pass/fail/skip counts.
+// If Config.Retries is set, a failing test is re-run on a freshly-restarted
+// worker (to get a clean DATA_DIR/Hono state) up to Retries additional times
+// before being recorded as failed. A test that only passes after a retry is
+// classified as "flaky" and recorded in o.flaky.
 func (o *Orchestrator) runSingleTest(ctx context.Context, worker *Worker, test TestInfo) (passed, failed, skipped int) {
+	maxAttempts := o.config.Retries + 1
+
+	var (
+		lastOutput   string
+		totalElapsed time.Duration
+	)
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			if o.config.Verbose {
+				slog.Info("retrying test after restart", "worker_id", worker.ID, "test_name", test.FuncName, "attempt", attempt, "max_attempts", maxAttempts)
+			}
+			if err := o.pool.restartWorkerServer(ctx, worker, o.config.Verbose); err != nil && o.config.Verbose {
+				slog.Warn("restart before retry failed", "worker_id", worker.ID, "error", err)
+			}
+		}
+
+		ok, output, elapsed, result := o.execGoTest(ctx, worker, test)
+		totalElapsed += elapsed
+		lastOutput = output
+		if worker.IsolatedEnv != nil {
+			result.DataDir = worker.IsolatedEnv.DataDir
+		}
+		o.reports.Add(result)
+
+		if ok {
+			if attempt > 1 {
+				slog.Warn("test flaky", "test_name", test.FuncName, "worker_id", worker.ID, "data_dir", result.DataDir, "attempt", attempt, "max_attempts", maxAttempts, "elapsed_ms", totalElapsed.Round(time.Millisecond).Milliseconds())
+				o.flaky.Record(test.FuncName, attempt, totalElapsed)
+			} else {
+				slog.Info("test passed", "test_name", test.FuncName, "worker_id", worker.ID, "data_dir", result.DataDir, "elapsed_ms", elapsed.Round(time.Millisecond).Milliseconds())
+			}
+
+			passed = 1
+			if cacheErr := o.cache.MarkPassed(test.FilePath); cacheErr != nil && o.config.Verbose {
+				slog.Warn("failed to mark test passed in cache", "test_name", test.FuncName, "error", cacheErr)
+			}
+			if o.resume != nil {
+				if resumeErr := o.resume.MarkPassed(test.FilePath); resumeErr != nil && o.config.Verbose {
+					slog.Warn("failed to mark test passed in resume state", "test_name", test.FuncName, "error", resumeErr)
+				}
+			}
+			return passed, failed, skipped
+		}
+	}
+
+	// All attempts failed
+	failed = 1
+	var dataDir string
+	if worker.IsolatedEnv != nil {
+		dataDir = worker.IsolatedEnv.DataDir
+	}
+	slog.Error("test failed", "test_name", test.FuncName, "worker_id", worker.ID, "data_dir", dataDir, "attempts", maxAttempts, "elapsed_ms", totalElapsed.Round(time.Millisecond).Milliseconds())
+
+	o.snapshotFailedWorker(ctx, worker, test)
+
+	if cacheErr := o.cache.MarkFailed(test.FilePath); cacheErr != nil && o.config.Verbose {
+		slog.Warn("failed to mark test failed in cache", "test_name", test.FuncName, "error", cacheErr)
+	}
+	if o.resume != nil {
+		if resumeErr := o.resume.MarkFailed(test.FilePath); resumeErr != nil && o.config.Verbose {
+			slog.Warn("failed to mark test failed in resume state", "test_name", test.FuncName, "error", resumeErr)
+		}
+	}
+
+	if o.config.Verbose {
+		fmt.Println("--- Output ---")
+		fmt.Println(lastOutput)
+		fmt.Println("--- End Output ---")
+	} else {
+		// Even in non-verbose mode, show some context for failures
+		lines := strings.Split(lastOutput, "\n")
+		for _, line := range lines {
+			if strings.Contains(line, "FAIL") || strings.Contains(line, "Error") ||
+				strings.Contains(line, "panic") || strings.Contains(line, "--- FAIL") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+
+	return passed, failed, skipped
+}
+
+// execGoTest runs `go test` once for a single test function against the
+// given worker and reports whether it passed, along with captured output,
+// elapsed time, and the aggregated per-test/per-subtest result decoded from
+// its "-json" event stream (see events.go). The test is run under a
+// timetrap: a soft per-test deadline (from a "// timetrap:" pragma, falling
+// back to Config.TestTimeout) independent of the overall context. On
+// timeout, diagnostics are captured and the worker's Hono server is
+// restarted before returning to the pool.
+//
+// Pass/fail is decided from the "-json" event stream rather than the
+// process exit code, so a failing subtest is recorded even when the parent
+// action is ambiguous (e.g. a panic after some subtests already passed).
+// The exit code is only consulted as a fallback when no events could be
+// decoded at all, or none of them reported an outcome for the test itself
+// (e.g. a compile error, which only yields package-level events).
+func (o *Orchestrator) execGoTest(ctx context.Context, worker *Worker, test TestInfo) (ok bool, output string, elapsed time.Duration, result *TestCaseResult) {
+	// Throttle on any rate-limited upstream services this test declares via
+	// a "// uses: openrouter,twocaptcha" pragma, so parallel workers don't
+	// collectively trip 429s against a shared API key.
+	usesLLM, usesCaptcha := testUses(test)
+	if usesLLM {
+		if err := o.llmLimiter.Wait(ctx); err != nil {
+			msg := fmt.Sprintf("rate limiter wait failed: %v", err)
+			return false, msg, 0, &TestCaseResult{Name: test.FuncName, Action: "fail", Output: msg, WorkerID: worker.ID}
+		}
+	}
+	if usesCaptcha {
+		if err := o.captchaLimiter.Wait(ctx); err != nil {
+			msg := fmt.Sprintf("rate limiter wait failed: %v", err)
+			return false, msg, 0, &TestCaseResult{Name: test.FuncName, Action: "fail", Output: msg, WorkerID: worker.ID}
+		}
+	}
+
 	// Build the go test command
 	// Run from project root, target ./test-orchestrator/e2e with -run filter
 	args := []string{
@@ -314,6 +624,7 @@ func (o *Orchestrator) runSingleTest(ctx context.Context, worker *Worker, test T
 		"./test-orchestrator/e2e",
 		"-run", fmt.Sprintf("^%s$", test.FuncName),
 		"-v",
+		"-json",    // structured event stream, decoded below for per-test/subtest results
 		"-count=1", // Disable test caching
 	}
 
@@ -322,10 +633,15 @@ func (o *Orchestrator) runSingleTest(ctx context.Context, worker *Worker, test T
 	// Set environment:
 	// - Worker environment (TEST_BASE_URL, DATA_DIR, API_TOKEN)
 	// - Secrets (OPENROUTER_API_KEY, TWOCAPTCHA_API_KEY, PROXY_SERVER)
+	// - Rate limit hints so helpers inside the test can self-throttle too
 	// - Inherit some system env vars
 	env := os.Environ()
 	env = append(env, worker.Env()...)
 	env = append(env, o.secrets.Env()...)
+	env = append(env,
+		fmt.Sprintf("SS_LLM_MIN_INTERVAL_MS=%d", o.llmLimiter.IntervalMillis()),
+		fmt.Sprintf("SS_CAPTCHA_MIN_INTERVAL_MS=%d", o.captchaLimiter.IntervalMillis()),
+	)
 	cmd.Env = env
 
 	// Capture output
@@ -333,56 +649,128 @@ func (o *Orchestrator) runSingleTest(ctx context.Context, worker *Worker, test T
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	softTimeout := test.SoftTimeout
+	if softTimeout <= 0 {
+		softTimeout = o.config.TestTimeout
+	}
+
 	// Log test start
 	startTime := time.Now()
 	if o.config.Verbose {
-		fmt.Printf("[worker-%d] Running %s\n", worker.ID, test.FuncName)
+		slog.Info("running test", "worker_id", worker.ID, "test_name", test.FuncName, "timetrap", softTimeout)
 	}
 
-	// Execute
-	err := cmd.Run()
-	elapsed := time.Since(startTime)
+	// Execute under the timetrap
+	timedOut, err := runWithTimetrap(cmd, softTimeout)
+	elapsed = time.Since(startTime)
+	output = stdout.String() + stderr.String()
 
-	// Parse output for results
-	output := stdout.String() + stderr.String()
-
-	if err != nil {
-		// Test failed
-		failed = 1
-		fmt.Printf("FAIL %s (%v)\n", test.FuncName, elapsed.Round(time.Millisecond))
+	if timedOut {
+		o.handleTestTimeout(ctx, worker, test, output)
+	}
 
-		// Mark as failed in cache
-		if cacheErr := o.cache.MarkFailed(test.FilePath); cacheErr != nil && o.config.Verbose {
-			fmt.Printf("[cache] Warning: failed to mark %s as failed: %v\n", test.FuncName, cacheErr)
+	// Adaptive backoff: if the captured output shows an upstream 429, halve
+	// the relevant limiter's rate and let it recover after a cooldown.
+	if detect429(output) {
+		if usesLLM {
+			o.llmLimiter.Throttle(o.config.Verbose)
+		}
+		if usesCaptcha {
+			o.captchaLimiter.Throttle(o.config.Verbose)
 		}
+	}
 
-		// Print failure output
-		if o.config.Verbose {
-			fmt.Println("--- Output ---")
-			fmt.Println(output)
-			fmt.Println("--- End Output ---")
+	events := decodeTestEvents(output)
+	result = aggregateTestEvents(events, test.FuncName, worker.ID)
+
+	if len(events) == 0 || result.Action == "" {
+		// No decodable JSON events, or no pass/fail/skip for the test
+		// itself (e.g. a compile error) - fall back to the process exit code.
+		ok = err == nil && !timedOut
+		if ok {
+			result.Action = "pass"
 		} else {
-			// Even in non-verbose mode, show some context for failures
-			lines := strings.Split(output, "\n")
-			for _, line := range lines {
-				if strings.Contains(line, "FAIL") || strings.Contains(line, "Error") ||
-					strings.Contains(line, "panic") || strings.Contains(line, "--- FAIL") {
-					fmt.Printf("    %s\n", line)
-				}
-			}
+			result.Action = "fail"
 		}
 	} else {
-		// Test passed
-		passed = 1
-		fmt.Printf("PASS %s (%v)\n", test.FuncName, elapsed.Round(time.Millisecond))
+		ok = !timedOut && result.Passed()
+	}
+
+	return ok, output, elapsed, result
+}
 
-		// Mark as passed in cache
-		if cacheErr := o.cache.MarkPassed(test.FilePath); cacheErr != nil && o.config.Verbose {
-			fmt.Printf("[cache] Warning: failed to mark %s as passed: %v\n", test.FuncName, cacheErr)
+// testUses reports whether test declares (via a "// uses:" pragma) that it
+// calls the OpenRouter and/or 2Captcha APIs.
+func testUses(test TestInfo) (usesLLM, usesCaptcha bool) {
+	for _, svc := range test.Uses {
+		switch strings.ToLower(svc) {
+		case "openrouter":
+			usesLLM = true
+		case "twocaptcha", "2captcha":
+			usesCaptcha = true
 		}
 	}
+	return usesLLM, usesCaptcha
+}
 
-	return passed, failed, skipped
+// handleTestTimeout dumps diagnostics for a test that exceeded its timetrap
+// deadline - the captured go test output, the last 500 lines of the worker's
+// tmux pane, and the worker's Hono server logs - to logs/<test>-timeout.txt,
+// then restarts the worker's Hono server to get a clean slate.
+func (o *Orchestrator) handleTestTimeout(ctx context.Context, worker *Worker, test TestInfo, goTestOutput string) {
+	var paneOutput string
+	if worker.TmuxSession != nil {
+		var paneErr error
+		paneOutput, paneErr = worker.TmuxSession.CapturePaneOutput(500)
+		if paneErr != nil {
+			paneOutput = fmt.Sprintf("(failed to capture tmux pane: %v)", paneErr)
+		}
+	} else {
+		paneOutput = fmt.Sprintf("(no tmux pane - isolation backend is %q)", worker.Isolator.Name())
+	}
+
+	honoLogs := "(no isolated environment)"
+	if worker.IsolatedEnv != nil {
+		logPath := filepath.Join(worker.IsolatedEnv.LogsDir, time.Now().UTC().Format("2006-01-02")+".jsonl")
+		if data, readErr := os.ReadFile(logPath); readErr == nil {
+			honoLogs = string(data)
+		} else {
+			honoLogs = fmt.Sprintf("(failed to read %s: %v)", logPath, readErr)
+		}
+	}
+
+	dump := fmt.Sprintf("=== go test output ===\n%s\n\n=== tmux pane (last 500 lines) ===\n%s\n\n=== Hono server logs ===\n%s\n",
+		goTestOutput, paneOutput, honoLogs)
+
+	timeoutPath := filepath.Join(o.config.LogsDir, fmt.Sprintf("%s-timeout.txt", test.FuncName))
+	if err := os.WriteFile(timeoutPath, []byte(dump), 0644); err != nil {
+		if o.config.Verbose {
+			slog.Warn("failed to write timeout dump", "worker_id", worker.ID, "path", timeoutPath, "error", err)
+		}
+	} else {
+		slog.Warn("test timed out, diagnostics written", "worker_id", worker.ID, "test_name", test.FuncName, "path", timeoutPath)
+	}
+
+	if err := o.pool.restartWorkerServer(ctx, worker, o.config.Verbose); err != nil && o.config.Verbose {
+		slog.Warn("restart after timeout failed", "worker_id", worker.ID, "error", err)
+	}
+}
+
+// snapshotFailedWorker archives a failed test's worker DataDir under
+// logs-dir/snapshots so the failure can be reproduced later via
+// RestoreIsolatedEnv, turning "it failed once in CI" into something a
+// developer can pull down and re-run against. Best-effort: a snapshot
+// failure is logged, not fatal to the test run.
+func (o *Orchestrator) snapshotFailedWorker(ctx context.Context, worker *Worker, test TestInfo) {
+	destDir := filepath.Join(o.config.LogsDir, "snapshots")
+
+	path, err := worker.Snapshot(ctx, destDir)
+	if err != nil {
+		slog.Warn("worker snapshot failed", "test_name", test.FuncName, "worker_id", worker.ID, "error", err)
+		return
+	}
+
+	slog.Info("worker snapshot saved", "test_name", test.FuncName, "worker_id", worker.ID, "path", path, "restore_src_dir", destDir)
 }
 
 // RegisterCleanup adds a cleanup function to be called during shutdown.
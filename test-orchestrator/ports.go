@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PortAllocator hands out free TCP ports from a configurable range,
+// verifying each candidate with a real bind (retrying past EADDRINUSE
+// instead of just guessing a range is free), and persists its leases to a
+// JSON file under the run's logs-dir so that a crashed orchestrator
+// doesn't leak ports across subsequent runs on the same host - the next
+// run loads the file and skips anything still marked leased.
+//
+// This replaces the old "Port: 9000 + workerID" scheme, which collided
+// whenever two smartScraper test runs (e.g. a CI shard and a local dev
+// run) targeted the same host at once.
+//
+// Leases are keyed by worker ID rather than an opaque lease ID, so the
+// persisted state file doubles as a record of which worker is holding which
+// port - useful for tracking down a leaked port after an orchestrator crash.
+type PortAllocator struct {
+	rangeStart int
+	rangeEnd   int
+	stateFile  string
+
+	mu     sync.Mutex
+	leases map[int]int // worker ID -> port
+}
+
+// ParsePortRange parses a "start-end" flag value, e.g. "9000-9099".
+func ParsePortRange(raw string) (start int, end int, err error) {
+	lo, hi, ok := strings.Cut(raw, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --port-range value %q, expected start-end", raw)
+	}
+
+	start, err = strconv.Atoi(lo)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --port-range start %q: %w", lo, err)
+	}
+	end, err = strconv.Atoi(hi)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --port-range end %q: %w", hi, err)
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("--port-range end %d is before start %d", end, start)
+	}
+
+	return start, end, nil
+}
+
+// NewPortAllocator creates an allocator over [rangeStart, rangeEnd], loading
+// any leases left behind under stateFile by a previous, possibly crashed,
+// run.
+func NewPortAllocator(rangeStart, rangeEnd int, stateFile string) (*PortAllocator, error) {
+	a := &PortAllocator{
+		rangeStart: rangeStart,
+		rangeEnd:   rangeEnd,
+		stateFile:  stateFile,
+		leases:     make(map[int]int),
+	}
+
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return a, nil
+		}
+		return nil, fmt.Errorf("failed to read port lease state %s: %w", stateFile, err)
+	}
+	if len(data) == 0 {
+		return a, nil
+	}
+	if err := json.Unmarshal(data, &a.leases); err != nil {
+		return nil, fmt.Errorf("failed to parse port lease state %s: %w", stateFile, err)
+	}
+
+	return a, nil
+}
+
+// Reserve leases one free port per given worker ID and returns a map from
+// worker ID to leased port. Each candidate port is verified with a real TCP
+// bind (retrying past EADDRINUSE) before being leased, so Reserve is safe to
+// call even when other processes on the host are allocating ports
+// concurrently.
+func (a *PortAllocator) Reserve(workerIDs []int) (map[int]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ports := make(map[int]int, len(workerIDs))
+	for _, id := range workerIDs {
+		port, err := a.reserveOneLocked(id)
+		if err != nil {
+			return nil, err
+		}
+		ports[id] = port
+	}
+
+	if err := a.saveLocked(); err != nil {
+		return nil, err
+	}
+
+	return ports, nil
+}
+
+func (a *PortAllocator) reserveOneLocked(workerID int) (int, error) {
+	leased := make(map[int]bool, len(a.leases))
+	for _, port := range a.leases {
+		leased[port] = true
+	}
+
+	for port := a.rangeStart; port <= a.rangeEnd; port++ {
+		if leased[port] {
+			continue
+		}
+
+		ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			// Most likely EADDRINUSE from some unrelated process; try the
+			// next candidate in the range instead of failing outright.
+			continue
+		}
+		ln.Close()
+
+		a.leases[workerID] = port
+		return port, nil
+	}
+
+	return 0, fmt.Errorf("%w: no free port in range %d-%d", ErrNoPortAvailable, a.rangeStart, a.rangeEnd)
+}
+
+// Release returns workerID's leased port to the pool of free ports and
+// persists the updated lease state.
+func (a *PortAllocator) Release(workerID int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.leases, workerID)
+
+	return a.saveLocked()
+}
+
+func (a *PortAllocator) saveLocked() error {
+	data, err := json.MarshalIndent(a.leases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal port lease state: %w", err)
+	}
+	if err := os.WriteFile(a.stateFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write port lease state %s: %w", a.stateFile, err)
+	}
+	return nil
+}
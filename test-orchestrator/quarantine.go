@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// QuarantineFile is the default filename for the test quarantine list.
+const QuarantineFile = ".test-quarantine"
+
+// LoadQuarantine reads the quarantine list file and returns the set of
+// quarantined test function names. Blank lines and lines starting with
+// "#" are ignored. A missing file is not an error - it just means no
+// tests are currently quarantined.
+func LoadQuarantine() (map[string]bool, error) {
+	quarantined := make(map[string]bool)
+
+	file, err := os.Open(QuarantineFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return quarantined, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		quarantined[line] = true
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return quarantined, nil
+}
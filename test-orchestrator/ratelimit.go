@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// usesRegex matches a "// uses: openrouter,twocaptcha" pragma comment placed
+// above a test function declaration, declaring which rate-limited upstream
+// services it calls.
+var usesRegex = regexp.MustCompile(`^//\s*uses:\s*(.+)`)
+
+// RateLimitCooldown is how long an AdaptiveLimiter waits after the last
+// observed 429 before restoring its base rate.
+const RateLimitCooldown = 60 * time.Second
+
+// AdaptiveLimiter wraps a golang.org/x/time/rate.Limiter that halves its
+// effective rate when the service it guards reports a 429, and restores the
+// base rate once no further 429s have been observed for RateLimitCooldown.
+type AdaptiveLimiter struct {
+	name      string
+	baseLimit rate.Limit
+	limiter   *rate.Limiter
+	cooldown  time.Duration
+	mu        sync.Mutex
+	restoreAt time.Time
+}
+
+// NewAdaptiveLimiter creates a limiter allowing ratePerSec requests/second
+// with a burst of 1, so callers queue rather than burst.
+func NewAdaptiveLimiter(name string, ratePerSec float64, cooldown time.Duration) *AdaptiveLimiter {
+	limit := rate.Limit(ratePerSec)
+	return &AdaptiveLimiter{
+		name:      name,
+		baseLimit: limit,
+		limiter:   rate.NewLimiter(limit, 1),
+		cooldown:  cooldown,
+	}
+}
+
+// Wait blocks until a token is available, restoring the base rate first if
+// the cooldown window has elapsed since the last observed 429.
+func (a *AdaptiveLimiter) Wait(ctx context.Context) error {
+	a.maybeRestore()
+	return a.limiter.Wait(ctx)
+}
+
+// IntervalMillis returns the current minimum interval between requests, in
+// milliseconds, so the child `go test` process can self-throttle too.
+func (a *AdaptiveLimiter) IntervalMillis() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	limit := a.limiter.Limit()
+	if limit <= 0 {
+		return 0
+	}
+	return int64(1000 / float64(limit))
+}
+
+// Throttle halves the effective rate (down to a small floor so progress
+// never fully stalls) and schedules a restore to the base rate after the
+// cooldown window.
+func (a *AdaptiveLimiter) Throttle(verbose bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	const floor = rate.Limit(0.05)
+
+	halved := a.limiter.Limit() / 2
+	if halved < floor {
+		halved = floor
+	}
+	a.limiter.SetLimit(halved)
+	a.restoreAt = time.Now().Add(a.cooldown)
+
+	if verbose {
+		fmt.Printf("[ratelimit] %s: observed 429, halving rate to %.3f/s (restoring after %v)\n", a.name, float64(halved), a.cooldown)
+	}
+}
+
+// maybeRestore restores the base rate once the cooldown window has elapsed
+// since the last Throttle call.
+func (a *AdaptiveLimiter) maybeRestore() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.restoreAt.IsZero() || time.Now().Before(a.restoreAt) {
+		return
+	}
+	a.limiter.SetLimit(a.baseLimit)
+	a.restoreAt = time.Time{}
+}
+
+// detect429 is a best-effort scan of captured test output for signs that an
+// upstream service returned a 429 (Too Many Requests).
+func detect429(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "429") &&
+		(strings.Contains(lower, "too many requests") || strings.Contains(lower, "rate limit"))
+}
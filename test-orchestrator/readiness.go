@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReadinessAttempt records the outcome of a single probe against a single
+// gate of a ReadinessPlan, for diagnosing flaky worker startup (an opaque
+// "timed out waiting for /health" told you nothing about whether the
+// process never started, started but never opened its port, or started
+// fine but errored on its first real request).
+type ReadinessAttempt struct {
+	Gate    string
+	Attempt int
+	OK      bool
+	Latency time.Duration
+	Err     string
+	At      time.Time
+}
+
+// ReadinessReport accumulates every ReadinessAttempt made by a
+// ReadinessPlan.Wait call, across all of its gates, in order. Worker
+// attaches the report from its most recent WaitReady call as
+// Worker.LastReadiness.
+type ReadinessReport struct {
+	mu       sync.Mutex
+	Attempts []ReadinessAttempt
+}
+
+func (r *ReadinessReport) record(a ReadinessAttempt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Attempts = append(r.Attempts, a)
+}
+
+// Snapshot returns a copy of the attempts recorded so far.
+func (r *ReadinessReport) Snapshot() []ReadinessAttempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]ReadinessAttempt, len(r.Attempts))
+	copy(out, r.Attempts)
+	return out
+}
+
+// gateProbe runs one attempt of a readiness gate. A non-zero retryAfter
+// lets a probe (e.g. one that hit a 429/503) tell Wait to honor the
+// server's own hint instead of the local jittered backoff, matching
+// HealthChecker's existing Retry-After handling.
+type gateProbe func(ctx context.Context) (retryAfter time.Duration, err error)
+
+type readinessGate struct {
+	name  string
+	probe gateProbe
+}
+
+// ReadinessPlan waits for a worker to come up through an ordered sequence
+// of gates, each stronger evidence than the last, rather than a single
+// opaque /health poll: TCP-connect (is anything even listening), HTTP
+// /health (is the app layer up), and optionally an application-level probe
+// (can it actually serve a real request). Retries within a gate use
+// exponential backoff with full jitter: delay = rand(0, min(cap,
+// base*2^attempt)), so many workers retrying at once don't all land on the
+// same instant.
+type ReadinessPlan struct {
+	gates       []readinessGate
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// NewReadinessPlan builds the standard gate sequence for a worker listening
+// on port at baseURL. If appProbeURL is non-empty, a third gate POSTs a
+// scrape request for that known-good fixture URL (e.g.
+// "https://httpbin.org/html") to baseURL+"/api/scrape" using token for
+// auth, and requires a 200 - the strongest evidence the server is actually
+// usable rather than merely listening. An empty appProbeURL skips that
+// gate, since it depends on outbound network access the other two don't.
+func NewReadinessPlan(baseURL string, port int, appProbeURL, token string) *ReadinessPlan {
+	gates := []readinessGate{
+		{name: "tcp-connect", probe: tcpConnectProbe(port)},
+		{name: "http-health", probe: httpHealthProbe(&HealthChecker{URL: baseURL + "/health"})},
+	}
+	if appProbeURL != "" {
+		gates = append(gates, readinessGate{name: "app-scrape", probe: appScrapeProbe(baseURL+"/api/scrape", appProbeURL, token)})
+	}
+
+	return &ReadinessPlan{
+		gates:       gates,
+		baseBackoff: 100 * time.Millisecond,
+		maxBackoff:  2 * time.Second,
+	}
+}
+
+func tcpConnectProbe(port int) gateProbe {
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	return func(ctx context.Context) (time.Duration, error) {
+		d := net.Dialer{Timeout: 2 * time.Second}
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return 0, err
+		}
+		return 0, conn.Close()
+	}
+}
+
+func httpHealthProbe(h *HealthChecker) gateProbe {
+	return func(ctx context.Context) (time.Duration, error) {
+		ok, throttled, retryAfter, err := h.CheckOnce(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return 0, nil
+		}
+		if throttled {
+			return retryAfter, fmt.Errorf("server throttled (429/503)")
+		}
+		return 0, fmt.Errorf("not ready")
+	}
+}
+
+func appScrapeProbe(scrapeURL, fixtureURL, token string) gateProbe {
+	return func(ctx context.Context) (time.Duration, error) {
+		body := strings.NewReader(fmt.Sprintf(`{"url":%q}`, fixtureURL))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, scrapeURL, body)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		client := &http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return 0, nil
+	}
+}
+
+// Wait runs each gate in order, retrying with exponential-backoff-with-
+// full-jitter until it succeeds or timeout elapses, recording every attempt
+// into report. onAttempt, if non-nil, is called after each attempt so
+// verbose mode and slog can both observe progress the way
+// HealthChecker.WaitWithProgress's callback does.
+func (p *ReadinessPlan) Wait(ctx context.Context, timeout time.Duration, report *ReadinessReport, onAttempt func(gate string, attempt int, ok bool, err error)) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, gate := range p.gates {
+		attempt := 0
+		for {
+			attempt++
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w: gate %q never became ready", ErrHealthCheckTimeout, gate.name)
+			default:
+			}
+
+			start := time.Now()
+			retryAfter, err := gate.probe(ctx)
+			latency := time.Since(start)
+
+			ok := err == nil
+			errMsg := ""
+			if err != nil {
+				errMsg = err.Error()
+			}
+
+			report.record(ReadinessAttempt{Gate: gate.name, Attempt: attempt, OK: ok, Latency: latency, Err: errMsg, At: start})
+			if onAttempt != nil {
+				onAttempt(gate.name, attempt, ok, err)
+			}
+
+			if ok {
+				break
+			}
+
+			wait := fullJitterBackoff(p.baseBackoff, p.maxBackoff, attempt)
+			if retryAfter > 0 {
+				wait = retryAfter
+				if capped := p.maxBackoff * 4; wait > capped {
+					wait = capped
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w: gate %q never became ready: %v", ErrHealthCheckTimeout, gate.name, err)
+			case <-time.After(wait):
+			}
+		}
+	}
+
+	return nil
+}
+
+// fullJitterBackoff implements the "full jitter" retry formula: a random
+// duration between 0 and min(cap, base*2^attempt), which spreads out
+// concurrent retries better than plain exponential backoff.
+func fullJitterBackoff(base, cap time.Duration, attempt int) time.Duration {
+	upper := float64(base) * math.Pow(2, float64(attempt))
+	if upper > float64(cap) {
+		upper = float64(cap)
+	}
+	if upper < 1 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(upper)))
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ReportCollector accumulates the aggregated JSON result of every test run
+// across the whole orchestrator invocation, so the --report=jsonl:path and
+// --report=junit:path writers can produce their output once the run
+// finishes. It's safe for concurrent use by the parallel test runners.
+type ReportCollector struct {
+	mu      sync.Mutex
+	results []*TestCaseResult
+}
+
+// NewReportCollector creates an empty collector.
+func NewReportCollector() *ReportCollector {
+	return &ReportCollector{}
+}
+
+// Add records the result of one top-level test, subtests attached.
+func (c *ReportCollector) Add(result *TestCaseResult) {
+	if result == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = append(c.results, result)
+}
+
+// Flush writes every configured --report=kind:path sink. kind is "jsonl",
+// "json", or "junit". A malformed or unknown spec is collected into the returned error
+// but doesn't stop the remaining sinks from being written.
+func (c *ReportCollector) Flush(specs []string) error {
+	c.mu.Lock()
+	results := make([]*TestCaseResult, len(c.results))
+	copy(results, c.results)
+	c.mu.Unlock()
+
+	var errs []error
+	for _, spec := range specs {
+		kind, path, ok := strings.Cut(spec, ":")
+		if !ok || path == "" {
+			errs = append(errs, fmt.Errorf("invalid --report value %q, expected kind:path", spec))
+			continue
+		}
+
+		var err error
+		switch kind {
+		case "jsonl":
+			err = writeJSONLReport(path, results)
+		case "json":
+			err = writeJSONSummaryReport(path, results)
+		case "junit":
+			err = writeJUnitReport(path, results)
+		default:
+			err = fmt.Errorf("unknown report kind %q (want jsonl, json, or junit)", kind)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("--report=%s: %w", spec, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("report errors: %v", errs)
+	}
+	return nil
+}
+
+// jsonlRecord is one line of the --report=jsonl:path output: a flattened
+// view of a TestCaseResult, top-level tests and subtests alike.
+type jsonlRecord struct {
+	Test     string  `json:"test"`
+	Action   string  `json:"action"`
+	ElapsedS float64 `json:"elapsedSeconds"`
+	WorkerID int     `json:"workerId"`
+}
+
+// writeJSONLReport writes one newline-delimited JSON object per test and
+// subtest, in the order they were recorded.
+func writeJSONLReport(path string, results []*TestCaseResult) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+
+	var encodeErr error
+	var emit func(r *TestCaseResult)
+	emit = func(r *TestCaseResult) {
+		if encodeErr != nil {
+			return
+		}
+		encodeErr = enc.Encode(jsonlRecord{
+			Test:     r.Name,
+			Action:   r.Action,
+			ElapsedS: r.Elapsed.Seconds(),
+			WorkerID: r.WorkerID,
+		})
+		for _, sub := range r.Subtests {
+			emit(sub)
+		}
+	}
+	for _, r := range results {
+		emit(r)
+	}
+
+	return encodeErr
+}
+
+// maxOutputExcerpt bounds how much captured stdout/stderr is inlined into
+// the --report=json:path summary, so a verbose/looping test doesn't blow
+// up the summary file.
+const maxOutputExcerpt = 4000
+
+// jsonSummaryRecord is one entry of the --report=json:path summary: a
+// richer, CI-dashboard-friendly view of jsonlRecord that also carries the
+// worker's DATA_DIR, whether the test was served from the file-hash cache,
+// and a bounded excerpt of its captured output.
+type jsonSummaryRecord struct {
+	Test           string  `json:"test"`
+	Action         string  `json:"action"`
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+	WorkerID       int     `json:"workerId"`
+	DataDir        string  `json:"dataDir,omitempty"`
+	CacheHit       bool    `json:"cacheHit"`
+	OutputExcerpt  string  `json:"outputExcerpt,omitempty"`
+}
+
+// writeJSONSummaryReport writes a single JSON array of jsonSummaryRecord,
+// one per test and subtest, in the order they were recorded.
+func writeJSONSummaryReport(path string, results []*TestCaseResult) error {
+	var records []jsonSummaryRecord
+
+	var collect func(r *TestCaseResult)
+	collect = func(r *TestCaseResult) {
+		excerpt := r.Output
+		if len(excerpt) > maxOutputExcerpt {
+			excerpt = excerpt[:maxOutputExcerpt]
+		}
+		records = append(records, jsonSummaryRecord{
+			Test:           r.Name,
+			Action:         r.Action,
+			ElapsedSeconds: r.Elapsed.Seconds(),
+			WorkerID:       r.WorkerID,
+			DataDir:        r.DataDir,
+			CacheHit:       r.CacheHit,
+			OutputExcerpt:  excerpt,
+		})
+		for _, sub := range r.Subtests {
+			collect(sub)
+		}
+	}
+	for _, r := range results {
+		collect(r)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON summary: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// junitTestSuites is the root element of a JUnit-XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	Classname  string          `xml:"classname,attr"`
+	Time       float64         `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// writeJUnitReport emits a JUnit-XML file compatible with the CI tooling
+// (GitHub Actions, GitLab, Jenkins) that consumes it. Each subtest becomes
+// its own <testcase>, named "Parent/Child" the same way go test reports it;
+// the worker that ran the test is recorded as a <property>.
+func writeJUnitReport(path string, results []*TestCaseResult) error {
+	suite := junitTestSuite{Name: "test-orchestrator/e2e"}
+
+	var flatten func(r *TestCaseResult)
+	flatten = func(r *TestCaseResult) {
+		tc := junitTestCase{
+			Name:      r.Name,
+			Classname: "e2e",
+			Time:      r.Elapsed.Seconds(),
+			Properties: []junitProperty{
+				{Name: "workerId", Value: fmt.Sprintf("%d", r.WorkerID)},
+			},
+			SystemOut: r.Output,
+		}
+		if r.Action == "fail" {
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%s failed", r.Name),
+				Content: r.Output,
+			}
+			suite.Failures++
+		}
+		if r.Action == "skip" {
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.Time += tc.Time
+		suite.TestCases = append(suite.TestCases, tc)
+
+		for _, sub := range r.Subtests {
+			flatten(sub)
+		}
+	}
+
+	for _, r := range results {
+		flatten(r)
+	}
+
+	doc := junitTestSuites{Suites: []junitTestSuite{suite}}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+
+	return os.WriteFile(path, append([]byte(xml.Header), data...), 0644)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ResumeState is a disk-backed record of tests already completed by a prior,
+// interrupted run, keyed to an explicit --resume-from path (distinct from
+// the always-on .test-cache.json): distributed CI runners that get
+// restarted mid-run pass the same path back in to pick up where they left
+// off instead of re-running everything from scratch.
+type ResumeState struct {
+	Entries map[string]TestCacheEntry `json:"entries"`
+	path    string
+	mu      sync.Mutex
+}
+
+// LoadResumeState loads the resume state from path, or creates a new empty
+// one if the file doesn't exist yet.
+func LoadResumeState(path string) (*ResumeState, error) {
+	state := &ResumeState{
+		Entries: make(map[string]TestCacheEntry),
+		path:    path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read resume state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		// Corrupted state file, start fresh rather than fail the run.
+		return &ResumeState{
+			Entries: make(map[string]TestCacheEntry),
+			path:    path,
+		}, nil
+	}
+
+	state.path = path
+	return state, nil
+}
+
+// Save persists the resume state to disk.
+func (s *ResumeState) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal resume state: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write resume state file: %w", err)
+	}
+
+	return nil
+}
+
+// NeedsRun reports whether filePath's test still needs to run: it's always
+// true if the file has changed since the resume state was recorded, or if
+// the prior attempt failed. Unlike TestCache, it does not invalidate on a
+// helpers.go change - resume state is meant to survive a single run's
+// restarts, not to serve as a long-lived correctness cache.
+func (s *ResumeState) NeedsRun(filePath string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	currentHash, err := FileHash(filePath)
+	if err != nil {
+		return true
+	}
+
+	entry, exists := s.Entries[filePath]
+	if !exists {
+		return true
+	}
+
+	if entry.Hash != currentHash {
+		return true
+	}
+
+	return !entry.Passed
+}
+
+// MarkPassed records a passing test result and saves immediately.
+func (s *ResumeState) MarkPassed(filePath string) error {
+	s.mu.Lock()
+
+	hash, err := FileHash(filePath)
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to hash file: %w", err)
+	}
+
+	s.Entries[filePath] = TestCacheEntry{
+		Hash:    hash,
+		LastRun: time.Now().Unix(),
+		Passed:  true,
+	}
+
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// MarkFailed records a failing test result and saves immediately.
+func (s *ResumeState) MarkFailed(filePath string) error {
+	s.mu.Lock()
+
+	hash, err := FileHash(filePath)
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to hash file: %w", err)
+	}
+
+	s.Entries[filePath] = TestCacheEntry{
+		Hash:    hash,
+		LastRun: time.Now().Unix(),
+		Passed:  false,
+	}
+
+	s.mu.Unlock()
+	return s.Save()
+}
@@ -1,63 +1,86 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 // Secrets holds SmartScraper configuration secrets.
 type Secrets struct {
-	APIToken         string `yaml:"API_TOKEN"`
-	OpenRouterAPIKey string `yaml:"OPENROUTER_API_KEY"`
-	TwoCaptchaAPIKey string `yaml:"TWOCAPTCHA_API_KEY"`
-	ProxyServer      string `yaml:"PROXY_SERVER"`
+	APIToken         string `yaml:"API_TOKEN" json:"API_TOKEN"`
+	OpenRouterAPIKey string `yaml:"OPENROUTER_API_KEY" json:"OPENROUTER_API_KEY"`
+	TwoCaptchaAPIKey string `yaml:"TWOCAPTCHA_API_KEY" json:"TWOCAPTCHA_API_KEY"`
+	ProxyServer      string `yaml:"PROXY_SERVER" json:"PROXY_SERVER"`
 }
 
-// LoadSecrets loads secrets from environment variables or SOPS-encrypted secrets.yaml.
-// Environment variables take precedence (for CI). Falls back to SOPS decryption (for local dev).
+// defaultSecretsBackendOrder is the order backends are tried when
+// SECRETS_BACKEND isn't set, or as the fallback order after a preferred
+// backend once SECRETS_BACKEND names one of them.
+var defaultSecretsBackendOrder = []string{"env", "sops", "vault", "aws"}
+
+// LoadSecrets resolves secrets via a chain of SecretsProvider backends: env
+// vars, SOPS-encrypted secrets.yaml, HashiCorp Vault, and AWS Secrets
+// Manager. SECRETS_BACKEND selects which backend to try first ("env",
+// "sops", "vault", or "aws"); the rest are tried in defaultSecretsBackendOrder
+// as a fallback so a misconfigured preferred backend doesn't hard-fail a
+// run that has a working fallback available.
 func LoadSecrets(verbose bool) (*Secrets, error) {
-	// Check if required secrets are in environment variables
-	if apiToken := os.Getenv("API_TOKEN"); apiToken != "" {
-		if verbose {
-			fmt.Println("Loading secrets from environment variables")
-		}
-		return &Secrets{
-			APIToken:         apiToken,
-			OpenRouterAPIKey: os.Getenv("OPENROUTER_API_KEY"),
-			TwoCaptchaAPIKey: os.Getenv("TWOCAPTCHA_API_KEY"),
-			ProxyServer:      os.Getenv("PROXY_SERVER"),
-		}, nil
-	}
+	providers := secretsProviderChain(os.Getenv("SECRETS_BACKEND"))
 
-	// Fall back to SOPS decryption
-	if verbose {
-		fmt.Println("Decrypting secrets.yaml via SOPS")
-	}
+	var tried []string
+	var errs []error
 
-	cmd := exec.Command("sops", "-d", "secrets.yaml")
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	for _, p := range providers {
+		tried = append(tried, p.Name())
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("sops decrypt failed: %w\nstderr: %s", err, stderr.String())
-	}
+		secrets, err := p.Load()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if secrets == nil {
+			continue // not configured, fall through to the next backend
+		}
 
-	var secrets Secrets
-	if err := yaml.Unmarshal(stdout.Bytes(), &secrets); err != nil {
-		return nil, fmt.Errorf("failed to parse secrets.yaml: %w", err)
+		if verbose {
+			fmt.Printf("Secrets loaded successfully via %s backend\n", p.Name())
+		}
+		return secrets, nil
 	}
 
-	if verbose {
-		fmt.Println("Secrets loaded successfully")
+	return nil, fmt.Errorf("%w: tried %s: %v", ErrSecretDecryptFailed, strings.Join(tried, ", "), errs)
+}
+
+// secretsProviderChain builds the ordered list of SecretsProvider backends
+// to try, putting preferred first (if it names a known backend) and the
+// rest of defaultSecretsBackendOrder after it.
+func secretsProviderChain(preferred string) []SecretsProvider {
+	order := defaultSecretsBackendOrder
+	if preferred != "" {
+		reordered := []string{preferred}
+		for _, name := range defaultSecretsBackendOrder {
+			if name != preferred {
+				reordered = append(reordered, name)
+			}
+		}
+		order = reordered
 	}
 
-	return &secrets, nil
+	var providers []SecretsProvider
+	for _, name := range order {
+		switch name {
+		case "env":
+			providers = append(providers, &EnvSecretsProvider{})
+		case "sops":
+			providers = append(providers, &SOPSSecretsProvider{})
+		case "vault":
+			providers = append(providers, &VaultSecretsProvider{})
+		case "aws":
+			providers = append(providers, &AWSSecretsManagerProvider{})
+		}
+	}
+	return providers
 }
 
 // Env returns secrets as environment variable strings suitable for exec.Cmd.Env.
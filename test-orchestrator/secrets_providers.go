@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SecretsProvider loads Secrets from one backend. A nil, nil return means
+// the provider isn't configured (e.g. its required env vars are unset) and
+// LoadSecrets should fall through to the next provider in the chain; a
+// non-nil error means the provider was configured but failed.
+type SecretsProvider interface {
+	Name() string
+	Load() (*Secrets, error)
+}
+
+// EnvSecretsProvider reads secrets directly from environment variables.
+// This is the fastest path and what CI runs use.
+type EnvSecretsProvider struct{}
+
+func (p *EnvSecretsProvider) Name() string { return "env" }
+
+func (p *EnvSecretsProvider) Load() (*Secrets, error) {
+	apiToken := os.Getenv("API_TOKEN")
+	if apiToken == "" {
+		return nil, nil
+	}
+	return &Secrets{
+		APIToken:         apiToken,
+		OpenRouterAPIKey: os.Getenv("OPENROUTER_API_KEY"),
+		TwoCaptchaAPIKey: os.Getenv("TWOCAPTCHA_API_KEY"),
+		ProxyServer:      os.Getenv("PROXY_SERVER"),
+	}, nil
+}
+
+// SOPSSecretsProvider decrypts secrets.yaml via the `sops` CLI, the
+// long-standing local-dev path. It declines (nil, nil) when secrets.yaml
+// doesn't exist, rather than treating a missing file as a hard failure.
+type SOPSSecretsProvider struct{}
+
+func (p *SOPSSecretsProvider) Name() string { return "sops" }
+
+func (p *SOPSSecretsProvider) Load() (*Secrets, error) {
+	if _, err := os.Stat("secrets.yaml"); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	cmd := exec.Command("sops", "-d", "secrets.yaml")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("sops decrypt failed: %w\nstderr: %s", err, stderr.String())
+	}
+
+	var secrets Secrets
+	if err := yaml.Unmarshal(stdout.Bytes(), &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets.yaml: %w", err)
+	}
+
+	return &secrets, nil
+}
+
+// VaultSecretsProvider reads secrets from a HashiCorp Vault KV v2 mount via
+// the `vault` CLI, which already honors VAULT_ADDR/VAULT_TOKEN from the
+// environment. It declines when VAULT_ADDR is unset. If VAULT_TOKEN is
+// absent but VAULT_ROLE_ID/VAULT_SECRET_ID are present, it logs in via
+// AppRole first to obtain a token for the kv read.
+type VaultSecretsProvider struct{}
+
+func (p *VaultSecretsProvider) Name() string { return "vault" }
+
+func (p *VaultSecretsProvider) Load() (*Secrets, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, nil
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		roleID := os.Getenv("VAULT_ROLE_ID")
+		secretID := os.Getenv("VAULT_SECRET_ID")
+		if roleID == "" || secretID == "" {
+			return nil, fmt.Errorf("VAULT_ADDR set but neither VAULT_TOKEN nor VAULT_ROLE_ID/VAULT_SECRET_ID provided")
+		}
+
+		loginToken, err := vaultAppRoleLogin(roleID, secretID)
+		if err != nil {
+			return nil, fmt.Errorf("vault approle login failed: %w", err)
+		}
+		token = loginToken
+	}
+
+	path := os.Getenv("VAULT_SECRET_PATH")
+	if path == "" {
+		path = "secret/data/smartscraper"
+	}
+
+	cmd := exec.Command("vault", "kv", "get", "-format=json", path)
+	cmd.Env = append(os.Environ(), "VAULT_TOKEN="+token)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("vault kv get failed: %w\nstderr: %s", err, stderr.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse vault kv response: %w", err)
+	}
+
+	return &Secrets{
+		APIToken:         resp.Data.Data["API_TOKEN"],
+		OpenRouterAPIKey: resp.Data.Data["OPENROUTER_API_KEY"],
+		TwoCaptchaAPIKey: resp.Data.Data["TWOCAPTCHA_API_KEY"],
+		ProxyServer:      resp.Data.Data["PROXY_SERVER"],
+	}, nil
+}
+
+// vaultAppRoleLogin exchanges a role_id/secret_id pair for a client token
+// via `vault write auth/approle/login`.
+func vaultAppRoleLogin(roleID, secretID string) (string, error) {
+	cmd := exec.Command("vault", "write", "-format=json", "auth/approle/login",
+		"role_id="+roleID, "secret_id="+secretID)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w\nstderr: %s", err, stderr.String())
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return "", fmt.Errorf("failed to parse approle login response: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("approle login response had no client_token")
+	}
+	return resp.Auth.ClientToken, nil
+}
+
+// AWSSecretsManagerProvider reads secrets from an AWS Secrets Manager
+// secret via the `aws` CLI, which picks up credentials from the standard
+// AWS env vars / shared config / instance profile. It declines when
+// AWS_SECRETS_MANAGER_SECRET_ID is unset.
+type AWSSecretsManagerProvider struct{}
+
+func (p *AWSSecretsManagerProvider) Name() string { return "aws" }
+
+func (p *AWSSecretsManagerProvider) Load() (*Secrets, error) {
+	secretID := os.Getenv("AWS_SECRETS_MANAGER_SECRET_ID")
+	if secretID == "" {
+		return nil, nil
+	}
+
+	args := []string{"secretsmanager", "get-secret-value", "--secret-id", secretID, "--query", "SecretString", "--output", "text"}
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		args = append(args, "--region", region)
+	}
+
+	cmd := exec.Command("aws", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("aws secretsmanager get-secret-value failed: %w\nstderr: %s", err, stderr.String())
+	}
+
+	raw := strings.TrimSpace(stdout.String())
+
+	var secrets Secrets
+	if err := json.Unmarshal([]byte(raw), &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse SecretString as JSON: %w", err)
+	}
+
+	return &secrets, nil
+}
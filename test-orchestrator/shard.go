@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// ShardSpec partitions discovered tests deterministically across N
+// distributed CI runners via "--shard i/N": runner i owns a test iff
+// hash(test.FilePath + "::" + test.FuncName) % N == i.
+type ShardSpec struct {
+	Index int
+	Total int
+}
+
+// ParseShardSpec parses a "i/N" flag value, e.g. "0/4" for the first of
+// four shards. Index must satisfy 0 <= Index < Total.
+func ParseShardSpec(raw string) (*ShardSpec, error) {
+	index, total, ok := strings.Cut(raw, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid --shard value %q, expected i/N", raw)
+	}
+
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --shard index %q: %w", index, err)
+	}
+	n, err := strconv.Atoi(total)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --shard total %q: %w", total, err)
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("--shard total must be positive, got %d", n)
+	}
+	if i < 0 || i >= n {
+		return nil, fmt.Errorf("--shard index %d out of range [0, %d)", i, n)
+	}
+
+	return &ShardSpec{Index: i, Total: n}, nil
+}
+
+// Owns reports whether this shard is responsible for a test, based on a
+// stable hash of its file path + function name so sharding is deterministic
+// across runs and across runners.
+func (s *ShardSpec) Owns(test TestInfo) bool {
+	h := fnv.New32a()
+	h.Write([]byte(test.FilePath + "::" + test.FuncName))
+	return int(h.Sum32()%uint32(s.Total)) == s.Index
+}
+
+// filterByShard returns the subset of tests owned by shard.
+func filterByShard(tests []TestInfo, shard *ShardSpec) []TestInfo {
+	var owned []TestInfo
+	for _, test := range tests {
+		if shard.Owns(test) {
+			owned = append(owned, test)
+		}
+	}
+	return owned
+}
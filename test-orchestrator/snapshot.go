@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshot tars env's DataDir (sites.jsonc, stats.json, logs/) into
+// destDir/worker-{id}-{timestamp}.tar.zst and returns the archive path.
+// Callers should pause whatever is writing to DataDir first (see
+// Worker.Snapshot) so the archive reflects a consistent point in time.
+//
+// Not supported for a docker-volume-backed env (Kind == "docker") yet - see
+// dockerVolumeIsolation's doc comment for the same host-access limitation.
+func (env *IsolatedEnv) Snapshot(ctx context.Context, destDir string) (string, error) {
+	if env.Kind == "docker" {
+		return "", fmt.Errorf("snapshotting a docker-volume-backed DataDir is not supported yet")
+	}
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot dir: %w", err)
+	}
+
+	archivePath := filepath.Join(destDir, fmt.Sprintf("worker-%d-%d.tar.zst", env.ID, time.Now().Unix()))
+
+	// Shell out to tar rather than pulling in a zstd library, matching this
+	// package's existing preference for existing CLIs (docker, tmux, fuser)
+	// over new dependencies.
+	cmd := exec.CommandContext(ctx, "tar", "--zstd", "-cf", archivePath, "-C", filepath.Dir(env.DataDir), filepath.Base(env.DataDir))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to tar DataDir %s: %w (output: %s)", env.DataDir, err, strings.TrimSpace(string(output)))
+	}
+
+	return archivePath, nil
+}
+
+// RestoreIsolatedEnv extracts the most recent snapshot for workerID found
+// under srcDir into a fresh host directory and returns an IsolatedEnv
+// pointing at it, so a CI failure captured by Worker.Snapshot can be
+// reproduced and re-run locally.
+//
+// The returned env's Port is unset (0) - the original port may no longer be
+// free, so the caller should lease a fresh one via PortAllocator before
+// starting a worker against it.
+func RestoreIsolatedEnv(srcDir string, workerID int) (*IsolatedEnv, error) {
+	archive, err := latestSnapshotArchive(srcDir, workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDir := fmt.Sprintf("/tmp/smartscraper-restore-%d-%d", workerID, time.Now().Unix())
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIsolationCreateFailed, err)
+	}
+
+	cmd := exec.Command("tar", "--zstd", "-xf", archive, "-C", baseDir, "--strip-components=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.RemoveAll(baseDir)
+		return nil, fmt.Errorf("failed to extract snapshot %s: %w (output: %s)", archive, err, strings.TrimSpace(string(output)))
+	}
+
+	return &IsolatedEnv{
+		ID:        workerID,
+		DataDir:   baseDir,
+		SitesFile: filepath.Join(baseDir, "sites.jsonc"),
+		StatsFile: filepath.Join(baseDir, "stats.json"),
+		LogsDir:   filepath.Join(baseDir, "logs"),
+		Kind:      "tmpdir",
+	}, nil
+}
+
+// latestSnapshotArchive finds the most recently created snapshot for
+// workerID under srcDir. Archive names sort lexicographically by their
+// Unix-timestamp suffix, so the last match is also the most recent.
+func latestSnapshotArchive(srcDir string, workerID int) (string, error) {
+	pattern := filepath.Join(srcDir, fmt.Sprintf("worker-%d-*.tar.zst", workerID))
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to search for snapshots matching %s: %w", pattern, err)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no snapshot found for worker %d in %s", workerID, srcDir)
+	}
+
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
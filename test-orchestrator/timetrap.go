@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+// timetrapRegex matches a "// timetrap: <duration>" pragma comment placed
+// directly above a test function declaration.
+var timetrapRegex = regexp.MustCompile(`^//\s*timetrap:\s*(\S+)`)
+
+// GraceWindow is how long a test gets after its soft deadline (SIGQUIT)
+// before being force-killed (SIGKILL).
+const GraceWindow = 10 * time.Second
+
+// runWithTimetrap runs cmd, enforcing a soft per-test deadline independent
+// of any context the caller has set up. If cmd is still running after
+// softTimeout, its process group is sent SIGQUIT so the Go runtime dumps all
+// goroutine stacks into the captured output; if it is still running after a
+// further GraceWindow, it is sent SIGKILL. Returns whether the soft deadline
+// was exceeded.
+func runWithTimetrap(cmd *exec.Cmd, softTimeout time.Duration) (timedOut bool, err error) {
+	// Run the child in its own process group so a timeout can signal the
+	// whole tree (go test plus any subprocess it spawned), not just itself.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	timer := time.NewTimer(softTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return false, err
+
+	case <-timer.C:
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGQUIT)
+
+		select {
+		case err := <-done:
+			return true, err
+		case <-time.After(GraceWindow):
+			_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+			return true, <-done
+		}
+	}
+}
@@ -93,6 +93,57 @@ func (t *TmuxSession) Exists() bool {
 	return err == nil
 }
 
+// PanePID returns the PID of the process group leader running in the pane
+// (normally the pane's shell).
+func (t *TmuxSession) PanePID() (int, error) {
+	cmd := exec.Command("tmux", "-S", t.Socket, "list-panes", "-t", t.Name, "-F", "#{pane_pid}")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list panes for session %s: %w (output: %s)", t.Name, err, strings.TrimSpace(string(output)))
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected pane_pid output %q from session %s: %w", strings.TrimSpace(string(output)), t.Name, err)
+	}
+
+	return pid, nil
+}
+
+// Pause suspends the pane's entire process group with SIGSTOP - the same
+// effect as pressing Ctrl+Z at the pane, but reliable to script - so a
+// snapshot of the worker's DataDir (see IsolatedEnv.Snapshot) reflects a
+// consistent point in time instead of racing an in-flight write.
+func (t *TmuxSession) Pause() error {
+	pid, err := t.PanePID()
+	if err != nil {
+		return err
+	}
+
+	// A negative PID targets the whole process group (shell + its
+	// descendants, e.g. npm and the node process it spawns), not just the
+	// shell itself.
+	if output, err := exec.Command("kill", "-STOP", "-"+strconv.Itoa(pid)).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to SIGSTOP session %s process group: %w (output: %s)", t.Name, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// Resume reverses Pause by sending SIGCONT to the pane's process group.
+func (t *TmuxSession) Resume() error {
+	pid, err := t.PanePID()
+	if err != nil {
+		return err
+	}
+
+	if output, err := exec.Command("kill", "-CONT", "-"+strconv.Itoa(pid)).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to SIGCONT session %s process group: %w (output: %s)", t.Name, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
 // CapturePaneOutput captures the last N lines from the tmux pane
 func (t *TmuxSession) CapturePaneOutput(lines int) (string, error) {
 	// Use negative start to capture last N lines
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -43,44 +45,92 @@ func (s WorkerStatus) String() string {
 }
 
 // Worker manages a single test worker's lifecycle.
-// Each worker runs a Hono dev server in a tmux session
-// with an isolated file-based environment.
+// Each worker runs a Hono dev server - inside a tmux session by default, or
+// inside a Docker/Podman container when --isolation selects one - against an
+// isolated file-based environment provisioned by Isolation (--isolation-kind).
 type Worker struct {
 	ID              int
 	Port            int
-	TmuxSession     *TmuxSession
+	PortAllocator   *PortAllocator // leased w.Port; releases it back on Stop (see ports.go)
+	RunID           string         // correlation ID for this orchestrator invocation
+	TmuxSession     *TmuxSession   // nil unless Isolator.Name() == "tmux"
+	Isolator        Isolator
+	Isolation       Isolation // provisions/tears down IsolatedEnv; see isolation.go
 	IsolatedEnv     *IsolatedEnv
 	LogFile         *os.File
+	liveLog         *LiveLog // fans out LogFile writes to NewLogReader subscribers
+	AppProbeURL     string   // fixture URL for WaitReady's optional app-scrape gate; "" skips it
+	LastReadiness   *ReadinessReport // diagnostics from the most recent WaitReady call
 	Status          WorkerStatus
 	StartedAt       time.Time
 	LastHealthCheck time.Time
 	mu              sync.Mutex
 }
 
-// NewWorker creates a new worker with the given ID.
-// It creates a log file and tmux session, but does not start them.
-func NewWorker(id int, logsDir string) (*Worker, error) {
+// WorkerConfig bundles the parameters NewWorker needs. Workers now have two
+// independent pluggable backends - Isolator (process launch) and Isolation
+// (DATA_DIR provisioning) - so a struct reads better than a growing list of
+// positional string arguments.
+type WorkerConfig struct {
+	ID            int
+	Port          int            // leased from PortAllocator by the caller (see WorkerPool.Start)
+	PortAllocator *PortAllocator // released on Worker.Stop; nil if the port shouldn't be released
+	LogsDir       string
+	Isolator      string // "tmux" (default), "docker", or "podman" - see Isolator
+	IsolationKind string // "tmpdir" (default), "tmpfs", or "docker" - see Isolation
+	RunID         string // correlation ID propagated to the worker process via SS_RUN_ID
+	AppProbeURL   string // fixture URL for WaitReady's optional app-scrape gate; "" skips it
+}
+
+// NewWorker creates a new worker per cfg. It creates a log file and, for the
+// tmux Isolator, a tmux session - but does not start them.
+func NewWorker(cfg WorkerConfig) (*Worker, error) {
 	// Create log file
-	logPath := filepath.Join(logsDir, fmt.Sprintf("worker-%d.log", id))
+	logPath := filepath.Join(cfg.LogsDir, fmt.Sprintf("worker-%d.log", cfg.ID))
 	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log file: %w", err)
 	}
 
-	// Create tmux session (not started yet)
-	tmuxSession := NewTmuxSession(id)
+	isolator, err := NewIsolator(cfg.Isolator)
+	if err != nil {
+		return nil, err
+	}
+
+	isolation, err := NewIsolation(cfg.IsolationKind)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the tmux backend needs a tmux session; container backends manage
+	// their own process lifecycle via the docker/podman CLI.
+	var tmuxSession *TmuxSession
+	if isolator.Name() == "tmux" {
+		tmuxSession = NewTmuxSession(cfg.ID)
+	}
 
 	return &Worker{
-		ID:          id,
-		Port:        9000 + id,
-		TmuxSession: tmuxSession,
-		LogFile:     logFile,
-		Status:      StatusIdle,
+		ID:            cfg.ID,
+		Port:          cfg.Port,
+		PortAllocator: cfg.PortAllocator,
+		RunID:         cfg.RunID,
+		TmuxSession:   tmuxSession,
+		Isolation:     isolation,
+		Isolator:      isolator,
+		LogFile:       logFile,
+		liveLog:       NewLiveLog(logFile),
+		AppProbeURL:   cfg.AppProbeURL,
+		Status:        StatusIdle,
 	}, nil
 }
 
-// StartTmux starts the tmux session for this worker.
+// StartTmux starts the tmux session for this worker. A no-op for
+// non-tmux isolation backends.
 func (w *Worker) StartTmux(verbose bool) error {
+	if w.TmuxSession == nil {
+		return nil
+	}
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -99,7 +149,7 @@ func (w *Worker) SetupIsolation() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	env, err := CreateIsolatedEnv(w.ID)
+	env, err := w.Isolation.Setup(w.ID, w.Port)
 	if err != nil {
 		w.Status = StatusFailed
 		return err
@@ -109,8 +159,8 @@ func (w *Worker) SetupIsolation() error {
 	return nil
 }
 
-// StartHono starts the Hono dev server in the tmux session.
-func (w *Worker) StartHono(verbose bool) error {
+// StartHono starts the Hono dev server via this worker's Isolator.
+func (w *Worker) StartHono(ctx context.Context, verbose bool) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -125,28 +175,21 @@ func (w *Worker) StartHono(verbose bool) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	// Build the command with environment variables
-	cmd := fmt.Sprintf("cd %s && DATA_DIR=%s PORT=%d API_TOKEN=test-token-%d npm run dev",
-		cwd,
-		w.IsolatedEnv.DataDir,
-		w.Port,
-		w.ID,
-	)
+	env := append(w.Isolation.Env(w.IsolatedEnv), fmt.Sprintf("SS_RUN_ID=%s", w.RunID))
 
-	// Log the command
+	// Log the launch
 	timestamp := time.Now().Format(time.RFC3339)
-	logEntry := fmt.Sprintf("[%s] Starting Hono: %s\n", timestamp, cmd)
-	if _, err := w.LogFile.WriteString(logEntry); err != nil {
+	logEntry := fmt.Sprintf("[%s] Starting Hono via %s isolator (port %d)\n", timestamp, w.Isolator.Name(), w.Port)
+	if _, err := w.liveLog.Write([]byte(logEntry)); err != nil {
 		// Log write failure is not fatal, continue
 		if verbose {
 			fmt.Printf("[worker-%d] Warning: failed to write to log: %v\n", w.ID, err)
 		}
 	}
 
-	// Send command to tmux
-	if err := w.TmuxSession.SendCommand(cmd, verbose); err != nil {
+	if err := w.Isolator.Launch(ctx, w, cwd, env, verbose); err != nil {
 		w.Status = StatusFailed
-		return fmt.Errorf("%w: %v", ErrTmuxCommandFailed, err)
+		return err
 	}
 
 	w.StartedAt = time.Now()
@@ -179,32 +222,40 @@ func (w *Worker) CheckHealth(ctx context.Context) (bool, error) {
 	return resp.StatusCode == http.StatusOK, nil
 }
 
-// WaitReady waits for the worker's server to become healthy.
+// WaitReady waits for the worker's server to become ready, progressing
+// through a ReadinessPlan's layered gates (TCP-connect, HTTP /health, and
+// an optional app-level scrape probe) rather than a single /health poll -
+// see readiness.go. Every attempt across every gate is recorded into
+// w.LastReadiness for diagnosing flaky startup.
 func (w *Worker) WaitReady(ctx context.Context, timeout time.Duration, verbose bool) error {
-	url := fmt.Sprintf("http://127.0.0.1:%d/health", w.Port)
-
-	checker := NewHealthChecker(url, timeout)
+	token := fmt.Sprintf("test-token-%d", w.ID)
+	plan := NewReadinessPlan(w.URL(), w.Port, w.AppProbeURL, token)
+	report := &ReadinessReport{}
 
-	var progressFunc func(attempt int, elapsed time.Duration)
-	if verbose {
-		progressFunc = func(attempt int, elapsed time.Duration) {
-			fmt.Printf("[worker-%d] Health check attempt %d (elapsed: %v)\n", w.ID, attempt, elapsed.Round(time.Millisecond))
+	onAttempt := func(gate string, attempt int, ok bool, err error) {
+		if verbose {
+			if ok {
+				fmt.Printf("[worker-%d] Readiness gate %q passed (attempt %d)\n", w.ID, gate, attempt)
+			} else {
+				fmt.Printf("[worker-%d] Readiness gate %q attempt %d failed: %v\n", w.ID, gate, attempt, err)
+			}
 		}
+		slog.Debug("readiness gate attempt", "worker_id", w.ID, "gate", gate, "attempt", attempt, "ok", ok)
 	}
 
-	if err := checker.WaitWithProgress(ctx, progressFunc); err != nil {
-		w.mu.Lock()
-		w.Status = StatusFailed
-		w.mu.Unlock()
-		return err
-	}
+	waitErr := plan.Wait(ctx, timeout, report, onAttempt)
 
 	w.mu.Lock()
-	w.Status = StatusReady
-	w.LastHealthCheck = time.Now()
+	w.LastReadiness = report
+	if waitErr != nil {
+		w.Status = StatusFailed
+	} else {
+		w.Status = StatusReady
+		w.LastHealthCheck = time.Now()
+	}
 	w.mu.Unlock()
 
-	return nil
+	return waitErr
 }
 
 // Stop shuts down the worker, killing the tmux session and cleaning up isolation.
@@ -215,20 +266,33 @@ func (w *Worker) Stop(ctx context.Context) error {
 
 	var errs []error
 
-	// Kill tmux session
-	if w.TmuxSession != nil {
-		if err := w.TmuxSession.Kill(); err != nil {
-			errs = append(errs, fmt.Errorf("tmux kill: %w", err))
+	// Tear down whatever the isolator started (tmux session or container)
+	if w.Isolator != nil {
+		if err := w.Isolator.Teardown(w); err != nil {
+			errs = append(errs, fmt.Errorf("isolator teardown: %w", err))
 		}
 	}
 
 	// Cleanup isolated environment
 	if w.IsolatedEnv != nil {
-		if err := w.IsolatedEnv.Cleanup(); err != nil {
+		if err := w.Isolation.Cleanup(w.IsolatedEnv); err != nil {
 			errs = append(errs, fmt.Errorf("isolation cleanup: %w", err))
 		}
 	}
 
+	// Release this worker's leased port back to the pool so a later run (or
+	// a concurrent one on the same host) can reuse it.
+	if w.PortAllocator != nil {
+		if err := w.PortAllocator.Release(w.ID); err != nil {
+			errs = append(errs, fmt.Errorf("port release: %w", err))
+		}
+	}
+
+	// Unblock any live log subscribers before closing the underlying file.
+	if w.liveLog != nil {
+		w.liveLog.Close()
+	}
+
 	// Close log file
 	if w.LogFile != nil {
 		if err := w.LogFile.Close(); err != nil {
@@ -251,6 +315,56 @@ func (w *Worker) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Snapshot captures this worker's IsolatedEnv into destDir so a failing run
+// can be reproduced later: for a tmux-backed worker it pauses the Hono
+// process first (TmuxSession.Pause, reversed via Resume once the tar
+// finishes) so the archive is a consistent point-in-time copy rather than
+// one racing an in-flight write; container-backed workers have no
+// equivalent pause yet, so the archive is taken live for them. It returns
+// the archive path, restorable later via RestoreIsolatedEnv.
+func (w *Worker) Snapshot(ctx context.Context, destDir string) (string, error) {
+	w.mu.Lock()
+	env := w.IsolatedEnv
+	tmuxSession := w.TmuxSession
+	w.mu.Unlock()
+
+	if env == nil {
+		return "", fmt.Errorf("worker %d has no isolated environment to snapshot", w.ID)
+	}
+
+	if tmuxSession != nil {
+		if err := tmuxSession.Pause(); err != nil {
+			return "", fmt.Errorf("failed to pause worker %d before snapshot: %w", w.ID, err)
+		}
+		defer func() {
+			if err := tmuxSession.Resume(); err != nil {
+				fmt.Printf("[worker-%d] WARNING: failed to resume after snapshot: %v\n", w.ID, err)
+			}
+		}()
+	}
+
+	path, err := env.Snapshot(ctx, destDir)
+	if err != nil {
+		return "", fmt.Errorf("worker %d snapshot failed: %w", w.ID, err)
+	}
+
+	return path, nil
+}
+
+// NewLogReader returns a follow-cursor over this worker's log: it replays
+// whatever recent output is still buffered, then blocks for more until new
+// bytes are written, the worker stops, or the returned reader is closed.
+// This backs the orchestrator's live log endpoint. Only what the
+// orchestrator writes to the worker's log file (the launch lines from
+// StartHono) reaches it; the Hono server's own stdout/stderr stays in its
+// tmux pane or container and is not included.
+func (w *Worker) NewLogReader() (io.ReadCloser, error) {
+	if w.liveLog == nil {
+		return nil, fmt.Errorf("worker %d has no live log", w.ID)
+	}
+	return w.liveLog.NewReader(), nil
+}
+
 // URL returns the base URL for this worker's HTTP server.
 func (w *Worker) URL() string {
 	return fmt.Sprintf("http://127.0.0.1:%d", w.Port)
@@ -261,6 +375,7 @@ func (w *Worker) Env() []string {
 	env := []string{
 		fmt.Sprintf("TEST_BASE_URL=http://127.0.0.1:%d", w.Port),
 		fmt.Sprintf("API_TOKEN=test-token-%d", w.ID),
+		fmt.Sprintf("SS_RUN_ID=%s", w.RunID),
 	}
 
 	if w.IsolatedEnv != nil {
@@ -3,28 +3,54 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os/exec"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // WorkerPool manages a pool of test workers.
 type WorkerPool struct {
-	workers    []*Worker
-	maxWorkers int
-	logsDir    string
-	mu         sync.Mutex
-	available  chan *Worker
+	workers       []*Worker
+	maxWorkers    int
+	logsDir       string
+	isolation     string // "tmux" (default), "docker", or "podman" - see Isolator
+	isolationKind string // "tmpdir" (default), "tmpfs", or "docker" - see Isolation
+	runID         string // correlation ID propagated to workers via SS_RUN_ID
+	ports         *PortAllocator
+	appProbeURL   string // fixture URL for WaitReady's optional app-scrape gate; "" skips it
+	mu            sync.Mutex
+	available     chan *Worker
+	draining      int32 // set to 1 once Shutdown begins; Acquire then rejects new requests
 }
 
-// NewWorkerPool creates a new worker pool.
-func NewWorkerPool(maxWorkers int, logsDir string) *WorkerPool {
-	return &WorkerPool{
-		workers:    make([]*Worker, 0, maxWorkers),
-		maxWorkers: maxWorkers,
-		logsDir:    logsDir,
-		available:  make(chan *Worker, maxWorkers),
+// NewWorkerPool creates a new worker pool using the given Isolator backend
+// ("tmux", "docker", "podman"; "" defaults to "tmux") and Isolation backend
+// ("tmpdir", "tmpfs", "docker"; "" defaults to "tmpdir"). runID is propagated
+// to every worker's environment as SS_RUN_ID for log correlation. Worker
+// ports are leased from a PortAllocator covering [portRangeStart,
+// portRangeEnd], with lease state persisted under logsDir so a crashed run
+// doesn't leak ports into the next one.
+func NewWorkerPool(maxWorkers int, logsDir string, isolation string, isolationKind string, runID string, portRangeStart int, portRangeEnd int, appProbeURL string) (*WorkerPool, error) {
+	ports, err := NewPortAllocator(portRangeStart, portRangeEnd, filepath.Join(logsDir, "ports.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port allocator: %w", err)
 	}
+
+	return &WorkerPool{
+		workers:       make([]*Worker, 0, maxWorkers),
+		maxWorkers:    maxWorkers,
+		logsDir:       logsDir,
+		isolation:     isolation,
+		isolationKind: isolationKind,
+		runID:         runID,
+		ports:         ports,
+		appProbeURL:   appProbeURL,
+		available:     make(chan *Worker, maxWorkers),
+	}, nil
 }
 
 // Start initializes and starts all workers in the pool.
@@ -41,7 +67,16 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 
 	// Phase 1: Create workers and start tmux sessions in parallel
 	if verbose {
-		fmt.Printf("[pool] Phase 1: Starting %d tmux sessions...\n", p.maxWorkers)
+		slog.Info("starting tmux sessions", "count", p.maxWorkers)
+	}
+
+	workerIDs := make([]int, p.maxWorkers)
+	for i := range workerIDs {
+		workerIDs[i] = i
+	}
+	ports, err := p.ports.Reserve(workerIDs)
+	if err != nil {
+		return fmt.Errorf("phase 1 (port allocation): %w", err)
 	}
 
 	var wg sync.WaitGroup
@@ -52,7 +87,16 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 		go func(id int) {
 			defer wg.Done()
 
-			worker, err := NewWorker(id, p.logsDir)
+			worker, err := NewWorker(WorkerConfig{
+				ID:            id,
+				Port:          ports[id],
+				PortAllocator: p.ports,
+				LogsDir:       p.logsDir,
+				Isolator:      p.isolation,
+				IsolationKind: p.isolationKind,
+				RunID:         p.runID,
+				AppProbeURL:   p.appProbeURL,
+			})
 			if err != nil {
 				errChan <- fmt.Errorf("worker %d: %w", id, err)
 				return
@@ -68,7 +112,7 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 			p.mu.Unlock()
 
 			if verbose {
-				fmt.Printf("[pool] Worker %d tmux session started\n", id)
+				slog.Info("worker ready", "worker_id", id, "isolation", worker.Isolator.Name())
 			}
 		}(i)
 	}
@@ -86,7 +130,7 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 
 	// Phase 2: Setup isolation (ephemeral DATA_DIRs) in parallel
 	if verbose {
-		fmt.Printf("[pool] Phase 2: Setting up %d isolated environments...\n", p.maxWorkers)
+		slog.Info("setting up isolated environments", "count", p.maxWorkers)
 	}
 
 	errChan = make(chan error, p.maxWorkers)
@@ -101,7 +145,7 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 			}
 
 			if verbose {
-				fmt.Printf("[pool] Worker %d isolation ready: %s\n", w.ID, w.IsolatedEnv.DataDir)
+				slog.Info("worker isolation ready", "worker_id", w.ID, "data_dir", w.IsolatedEnv.DataDir)
 			}
 		}(worker)
 	}
@@ -115,7 +159,7 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 
 	// Phase 3: Start Hono servers in parallel
 	if verbose {
-		fmt.Printf("[pool] Phase 3: Starting %d Hono servers...\n", p.maxWorkers)
+		slog.Info("starting hono servers", "count", p.maxWorkers)
 	}
 
 	errChan = make(chan error, p.maxWorkers)
@@ -124,13 +168,13 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 		go func(w *Worker) {
 			defer wg.Done()
 
-			if err := w.StartHono(verbose); err != nil {
+			if err := w.StartHono(ctx, verbose); err != nil {
 				errChan <- fmt.Errorf("worker %d: %w", w.ID, err)
 				return
 			}
 
 			if verbose {
-				fmt.Printf("[pool] Worker %d Hono start command sent (port %d)\n", w.ID, w.Port)
+				slog.Info("hono start command sent", "worker_id", w.ID, "port", w.Port)
 			}
 		}(worker)
 	}
@@ -144,7 +188,7 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 
 	// Phase 4: Health check loop - poll every 200ms until ALL workers healthy
 	if verbose {
-		fmt.Printf("[pool] Phase 4: Waiting for %d workers to become healthy (timeout: %v)...\n", p.maxWorkers, healthTimeout)
+		slog.Info("waiting for workers to become healthy", "count", p.maxWorkers, "timeout", healthTimeout)
 	}
 
 	healthCtx, healthCancel := context.WithTimeout(ctx, healthTimeout)
@@ -197,7 +241,7 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 				if result.healthy {
 					healthyWorkers[result.id] = true
 					if verbose {
-						fmt.Printf("[pool] Worker %d is healthy\n", result.id)
+						slog.Info("worker is healthy", "worker_id", result.id)
 					}
 				}
 			}
@@ -205,7 +249,7 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 			// Check if all workers are healthy
 			if len(healthyWorkers) == p.maxWorkers {
 				if verbose {
-					fmt.Printf("[pool] All %d workers healthy\n", p.maxWorkers)
+					slog.Info("all workers healthy", "count", p.maxWorkers)
 				}
 
 				// Add all workers to available channel
@@ -233,6 +277,10 @@ func (p *WorkerPool) Start(ctx context.Context, healthTimeout time.Duration, ver
 // It blocks until a worker is available or the context/timeout expires.
 // If the acquired worker's server is unhealthy, it will attempt to restart it.
 func (p *WorkerPool) Acquire(ctx context.Context, timeout time.Duration, verbose bool) (*Worker, error) {
+	if atomic.LoadInt32(&p.draining) == 1 {
+		return nil, ErrWorkerNotAvailable
+	}
+
 	acquireCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -246,13 +294,13 @@ func (p *WorkerPool) Acquire(ctx context.Context, timeout time.Duration, verbose
 		healthy, _ := worker.CheckHealth(ctx)
 		if !healthy {
 			if verbose {
-				fmt.Printf("[pool] Worker %d unhealthy, attempting restart...\n", worker.ID)
+				slog.Info("worker unhealthy, attempting restart", "worker_id", worker.ID)
 			}
 
 			// Restart the Hono server
 			if err := p.restartWorkerServer(ctx, worker, verbose); err != nil {
 				if verbose {
-					fmt.Printf("[pool] Worker %d restart failed: %v\n", worker.ID, err)
+					slog.Warn("worker restart failed", "worker_id", worker.ID, "error", err)
 				}
 				// Put worker back and return error
 				p.available <- worker
@@ -261,7 +309,7 @@ func (p *WorkerPool) Acquire(ctx context.Context, timeout time.Duration, verbose
 		}
 
 		if verbose {
-			fmt.Printf("[pool] Acquired worker %d\n", worker.ID)
+			slog.Info("acquired worker", "worker_id", worker.ID)
 		}
 
 		return worker, nil
@@ -271,18 +319,27 @@ func (p *WorkerPool) Acquire(ctx context.Context, timeout time.Duration, verbose
 	}
 }
 
-// restartWorkerServer restarts the Hono server for a worker.
+// restartWorkerServer restarts the Hono server for a worker. For the tmux
+// backend this interrupts the process in place within the existing tmux
+// session (the original behavior); container backends have no equivalent of
+// sending Ctrl+C to "the same process", so they tear down and relaunch the
+// container instead.
 func (p *WorkerPool) restartWorkerServer(ctx context.Context, worker *Worker, verbose bool) error {
-	// Send Ctrl+C to stop any running process
-	if err := worker.TmuxSession.SendCommand("\x03", verbose); err != nil {
-		// Ignore error, might not have a running process
+	if worker.TmuxSession != nil {
+		// Send Ctrl+C to stop any running process
+		if err := worker.TmuxSession.SendCommand("\x03", verbose); err != nil {
+			// Ignore error, might not have a running process
+		}
+		// Wait a moment for the process to stop
+		time.Sleep(500 * time.Millisecond)
+	} else if worker.Isolator != nil {
+		if err := worker.Isolator.Teardown(worker); err != nil && verbose {
+			slog.Warn("worker isolator teardown before restart failed", "worker_id", worker.ID, "error", err)
+		}
 	}
 
-	// Wait a moment for the process to stop
-	time.Sleep(500 * time.Millisecond)
-
 	// Start Hono again
-	if err := worker.StartHono(verbose); err != nil {
+	if err := worker.StartHono(ctx, verbose); err != nil {
 		return fmt.Errorf("failed to start Hono: %w", err)
 	}
 
@@ -293,7 +350,7 @@ func (p *WorkerPool) restartWorkerServer(ctx context.Context, worker *Worker, ve
 	}
 
 	if verbose {
-		fmt.Printf("[pool] Worker %d restarted successfully\n", worker.ID)
+		slog.Info("worker restarted successfully", "worker_id", worker.ID)
 	}
 
 	return nil
@@ -308,17 +365,43 @@ func (p *WorkerPool) Release(w *Worker) {
 	p.available <- w
 }
 
-// Shutdown stops all workers and cleans up resources.
-func (p *WorkerPool) Shutdown(ctx context.Context) error {
+// Shutdown performs a graceful lame-duck shutdown of the pool:
+//  1. Stop accepting new Acquire calls (the "draining" gate).
+//  2. Wait up to lameDuckTimeout for currently-StatusRunning workers to
+//     finish their test and Release, so in-flight results aren't torn down
+//     mid-write.
+//  3. Attempt a graceful stop of each Hono server (via a /shutdown call or
+//     SIGINT through tmux) and poll CheckHealth for it to exit.
+//  4. Only then fall back to Worker.Stop (tmux kill-session) and
+//     forceKillPorts.
+func (p *WorkerPool) Shutdown(ctx context.Context, lameDuckTimeout time.Duration) error {
+	// Stop accepting new acquisitions immediately.
+	atomic.StoreInt32(&p.draining, 1)
+
 	p.mu.Lock()
 	workers := make([]*Worker, len(p.workers))
 	copy(workers, p.workers)
 	p.mu.Unlock()
 
-	// Stop all workers in parallel
+	// Lame-duck phase: wait for in-flight tests to release their worker.
+	deadline := time.Now().Add(lameDuckTimeout)
+	for p.runningCount(workers) > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Attempt a graceful stop of each Hono server before force-killing.
 	var wg sync.WaitGroup
-	errChan := make(chan error, len(workers))
+	for _, worker := range workers {
+		wg.Add(1)
+		go func(w *Worker) {
+			defer wg.Done()
+			p.gracefulStopWorker(ctx, w)
+		}(worker)
+	}
+	wg.Wait()
 
+	// Stop all workers (tmux session + isolation cleanup) in parallel.
+	errChan := make(chan error, len(workers))
 	for _, worker := range workers {
 		wg.Add(1)
 		go func(w *Worker) {
@@ -338,8 +421,9 @@ func (p *WorkerPool) Shutdown(ctx context.Context) error {
 		errs = append(errs, err)
 	}
 
-	// Force kill any remaining processes on ports 9000-9007
-	forceKillPorts()
+	// Force kill any remaining processes on this pool's worker ports, as
+	// a fallback in case the graceful stop and tmux kill didn't take.
+	p.forceKillPorts(workers)
 
 	// Drain available channel
 	close(p.available)
@@ -354,11 +438,59 @@ func (p *WorkerPool) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// forceKillPorts forcefully kills any processes listening on ports 9000-9007.
-func forceKillPorts() {
-	for port := 9000; port <= 9007; port++ {
+// runningCount returns how many of the given workers are currently executing
+// a test.
+func (p *WorkerPool) runningCount(workers []*Worker) int {
+	count := 0
+	for _, w := range workers {
+		w.mu.Lock()
+		if w.Status == StatusRunning {
+			count++
+		}
+		w.mu.Unlock()
+	}
+	return count
+}
+
+// gracefulStopWorker attempts to stop a worker's Hono server cleanly before
+// the pool falls back to tmux kill-session and forceKillPorts. It is
+// best-effort: Worker.Stop and forceKillPorts clean up regardless of whether
+// this succeeds.
+func (p *WorkerPool) gracefulStopWorker(ctx context.Context, w *Worker) {
+	// Prefer a graceful HTTP shutdown endpoint if the server exposes one.
+	shutdownURL := fmt.Sprintf("http://127.0.0.1:%d/shutdown", w.Port)
+	if req, err := http.NewRequestWithContext(ctx, http.MethodPost, shutdownURL, nil); err == nil {
+		client := &http.Client{Timeout: 2 * time.Second}
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	// Fall back to sending Ctrl+C through tmux so the dev server gets a
+	// chance to run its own shutdown hooks instead of being killed outright.
+	if w.TmuxSession != nil {
+		_ = w.TmuxSession.SendCommand("\x03", false)
+	}
+
+	// Give the server a short window to actually exit before moving on to
+	// hard cleanup.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		healthy, _ := w.CheckHealth(ctx)
+		if !healthy {
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// forceKillPorts forcefully kills any processes listening on this pool's
+// worker ports. Ports are derived from the workers themselves rather than a
+// hard-coded range, so pool sizes other than 8 work correctly.
+func (p *WorkerPool) forceKillPorts(workers []*Worker) {
+	for _, w := range workers {
 		// Use fuser to find and kill processes on the port
-		cmd := exec.Command("fuser", "-k", fmt.Sprintf("%d/tcp", port))
+		cmd := exec.Command("fuser", "-k", fmt.Sprintf("%d/tcp", w.Port))
 		_ = cmd.Run() // Ignore errors - port may not be in use
 	}
 }